@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// The key of accumulated errors in context.
+type errorsKey struct{}
+
+// The errors accumulated by ContextAddError, shared by the contexts derived from ContextWithErrors.
+type accumulatedErrors struct {
+	lock sync.Mutex
+	errs []error
+}
+
+// Derive ctx with the accumulator of errors, for the request which meets several
+// recoverable errors, to log them as one line at the end, for example, in the middleware:
+//		ctx = logger.ContextWithErrors(ctx)
+//		defer logger.LogAccumulated(ctx)
+// Then the callees add the errors to it, without returning ctx:
+//		logger.ContextAddError(ctx, err)
+// The accumulator is shared by the contexts derived from ctx, and safe for the goroutines
+// of request.
+// @remark The cid of ctx is kept, see PushPrefix.
+func ContextWithErrors(ctx Context) Context {
+	return withValue(ctx, errorsKey{}, &accumulatedErrors{})
+}
+
+// Add err to the accumulator of ctx, see ContextWithErrors. The nil err is ignored.
+// If ctx has no accumulator, the err is logged at Error level immediately, so it's
+// never lost.
+func ContextAddError(ctx Context, err error) {
+	if err == nil {
+		return
+	}
+
+	v, _ := valueOf(ctx, errorsKey{}).(*accumulatedErrors)
+	if v == nil {
+		printFields(Error, ctx, "accumulated errors", field{"count", 1}, field{"errors", err.Error()})
+		return
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.errs = append(v.errs, err)
+}
+
+// Log the accumulated errors of ctx as one Error line, with the count and the errors
+// joined by semicolon, then clear them, for example:
+//		[error] 2006/01/02 15:04:05.000000 [pid][cid] accumulated errors count=2 errors="dial timeout; no stream"
+// Nothing is logged if no error, see ContextWithErrors.
+func LogAccumulated(ctx Context) {
+	v, _ := valueOf(ctx, errorsKey{}).(*accumulatedErrors)
+	if v == nil {
+		return
+	}
+
+	v.lock.Lock()
+	errs := v.errs
+	v.errs = nil
+	v.lock.Unlock()
+	if len(errs) == 0 {
+		return
+	}
+
+	details := make([]string, 0, len(errs))
+	for _, err := range errs {
+		details = append(details, err.Error())
+	}
+	printFields(Error, ctx, "accumulated errors", field{"count", len(errs)}, field{"errors", strings.Join(details, "; ")})
+}
@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// The policy of async writer when the queue is full.
+type AsyncPolicy int
+
+const (
+	// Block the logging until the queue has room, the default policy, which never drops.
+	PolicyBlock AsyncPolicy = iota
+	// Drop the newest log, which keeps the queued ones.
+	PolicyDropNew
+	// Drop the oldest queued log to make room for the newest one, for the latest state
+	// matters most, such as dashboards.
+	PolicyDropOldest
+)
+
+// The names of policies, index by AsyncPolicy.
+var asyncPolicyNames = [...]string{"block", "drop-new", "drop-oldest"}
+
+// The name of policy, such as drop-new for PolicyDropNew, or unknown for invalid policy.
+func (v AsyncPolicy) String() string {
+	if v < PolicyBlock || v > PolicyDropOldest {
+		return "unknown"
+	}
+	return asyncPolicyNames[v]
+}
+
+// The logs dropped by async writers, index by AsyncPolicy.
+var asyncDropped [PolicyDropOldest + 1]uint64
+
+// Set the policy of async writer when the queue is full, default to PolicyBlock.
+// @remark The dropped logs are counted as dropped of SetHeartbeat, see AsyncDropped.
+func SetAsyncPolicy(policy AsyncPolicy) {
+	updateOptions(WithAsyncPolicy(policy))
+}
+
+// The total logs dropped by async writers for policy.
+func AsyncDropped(policy AsyncPolicy) uint64 {
+	if policy < PolicyBlock || policy > PolicyDropOldest {
+		return 0
+	}
+	return atomic.LoadUint64(&asyncDropped[policy])
+}
+
+// Create the writer which queues the logs and writes to w in background, so the slow w
+// never blocks logging, unless the queue of size is full, see SetAsyncPolicy, for example:
+//		logger.Switch(logger.NewAsyncWriter(f, 1024))
+//		defer logger.Close()
+// @remark Close writes all queued logs, then closes w if it's an io.Closer.
+func NewAsyncWriter(w io.Writer, size int) io.WriteCloser {
+	if size <= 0 {
+		size = 1
+	}
+
+	v := &asyncWriter{w: w, ring: make([][]byte, size), done: make(chan struct{})}
+	v.cond = sync.NewCond(&v.lock)
+	go v.serve()
+	return v
+}
+
+// The async writer, with a ring of queued logs.
+type asyncWriter struct {
+	w    io.Writer
+	done chan struct{}
+
+	lock sync.Mutex
+	// Signal when the ring is changed or closed.
+	cond *sync.Cond
+	// The ring, where the oldest is at head.
+	ring        [][]byte
+	head, count int
+	closed      bool
+	// Whether a log is being written to w.
+	writing bool
+}
+
+func (v *asyncWriter) Write(p []byte) (int, error) {
+	policy := loadOptions().asyncPolicy
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	for !v.closed && v.count == len(v.ring) && policy == PolicyBlock {
+		v.cond.Wait()
+	}
+	if v.closed {
+		return 0, os.ErrClosed
+	}
+
+	if v.count == len(v.ring) {
+		atomic.AddUint64(&asyncDropped[policy], 1)
+		atomic.AddUint64(&stats.dropped, 1)
+		reportf("async queue of %v is full, drop by policy %v", len(v.ring), policy)
+		if policy == PolicyDropNew {
+			return len(p), nil
+		}
+		v.ring[v.head] = nil
+		v.head = (v.head + 1) % len(v.ring)
+		v.count--
+	}
+
+	v.ring[(v.head+v.count)%len(v.ring)] = append([]byte(nil), p...)
+	v.count++
+	v.cond.Broadcast()
+	return len(p), nil
+}
+
+func (v *asyncWriter) serve() {
+	defer close(v.done)
+
+	for {
+		v.lock.Lock()
+		if v.writing {
+			v.writing = false
+			v.cond.Broadcast()
+		}
+		for !v.closed && v.count == 0 {
+			v.cond.Wait()
+		}
+		if v.count == 0 {
+			v.lock.Unlock()
+			return
+		}
+
+		p := v.ring[v.head]
+		v.ring[v.head] = nil
+		v.head = (v.head + 1) % len(v.ring)
+		v.count--
+		v.writing = true
+		v.cond.Broadcast()
+		v.lock.Unlock()
+
+		if _, err := v.w.Write(p); err != nil {
+			atomic.AddUint64(&stats.dropped, 1)
+			handleError(loadOptions(), err)
+		}
+	}
+}
+
+// Wait until the queued logs are written to w, see Rotate.
+func (v *asyncWriter) flush() {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	for !v.closed && (v.count > 0 || v.writing) {
+		v.cond.Wait()
+	}
+}
+
+func (v *asyncWriter) Close() error {
+	v.lock.Lock()
+	closed := v.closed
+	v.closed = true
+	v.cond.Broadcast()
+	v.lock.Unlock()
+
+	<-v.done
+	if closed {
+		return nil
+	}
+	if w, ok := v.w.(io.Closer); ok {
+		return w.Close()
+	}
+	return nil
+}
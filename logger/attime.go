@@ -0,0 +1,45 @@
+package logger
+
+import "time"
+
+// The arg to set the time of log, see AtTime.
+type atTimeArg time.Time
+
+// Wrap ts as an arg which sets the time of log instead of now, for the events which
+// occurred earlier, such as replayed from a queue, for example:
+//		logger.T(ctx, "order created", logger.AtTime(msg.Timestamp))
+// Then the time of text log, and the ts field of JSON log, is msg.Timestamp.
+// The arg is removed before formatting, so it's not in the message and not counted
+// by the verbs of Printf. The zero time is ignored, to use now.
+// @remark The rate limit, quota and stats still use now, while the delta is from ts.
+func AtTime(ts time.Time) interface{} {
+	return atTimeArg(ts)
+}
+
+// Remove the AtTime args of entry, return the time of the last non-zero one,
+// false if none.
+func (e *entry) takeAtTime() (t time.Time, ok bool) {
+	n := 0
+	for _, arg := range e.args {
+		if _, is := arg.(atTimeArg); is {
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+
+	// Copy the args, which may be reused by caller.
+	args := make([]interface{}, 0, len(e.args)-n)
+	for _, arg := range e.args {
+		if ts, is := arg.(atTimeArg); is {
+			if !time.Time(ts).IsZero() {
+				t, ok = time.Time(ts), true
+			}
+			continue
+		}
+		args = append(args, arg)
+	}
+	e.args = args
+	return
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// The writer which buffers the logs, such as bufio.Writer, see SetAutoFlush.
+type Flusher interface {
+	Flush() error
+}
+
+var autoFlushLock sync.Mutex
+var autoFlushStop, autoFlushDone chan struct{}
+
+// Flush the underlayer io and the sinks which are Flusher every interval, so the buffered
+// writers are safe to use, for example, to write file by bufio.Writer for performance:
+//		logger.Switch(bufio.NewWriterSize(f, 64*1024))
+//		logger.SetAutoFlush(time.Second)
+//		defer logger.Close()
+// Then at most the logs of last interval are lost when crash. Set interval to 0 to stop it,
+// and it's also stopped by Close, which always flushes the Flusher writers, even not set.
+// @remark The writers are flushed under the lock of writing logs, for bufio.Writer is not
+// 	safe for concurrent use, so the Flusher behind NewAsyncWriter is never flushed, for it's
+// 	written by the goroutine of async writer.
+func SetAutoFlush(interval time.Duration) {
+	autoFlushLock.Lock()
+	defer autoFlushLock.Unlock()
+
+	// Wait for the goroutine to quit, so no flush after stopped.
+	if autoFlushStop != nil {
+		close(autoFlushStop)
+		<-autoFlushDone
+		autoFlushStop, autoFlushDone = nil, nil
+	}
+	if interval <= 0 {
+		return
+	}
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	autoFlushStop, autoFlushDone = stop, done
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := flushWriters(); err != nil {
+					handleError(loadOptions(), err)
+				}
+			}
+		}
+	}()
+}
+
+// Flush the underlayer io and the sinks which are Flusher, return the first error.
+func flushWriters() (err error) {
+	writers := currentWriters(loadOptions())
+
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	for _, w := range writers {
+		if f, ok := w.(Flusher); ok {
+			if r := f.Flush(); r != nil && err == nil {
+				err = r
+			}
+		}
+	}
+	return
+}
+
+// The underlayer io of each level and the sinks, without duplicated.
+func currentWriters(o *options) []io.Writer {
+	var writers []io.Writer
+	add := func(w io.Writer) {
+		for _, v := range writers {
+			if sameWriter(v, w) {
+				return
+			}
+		}
+		writers = append(writers, w)
+	}
+
+	for _, l := range []Logger{Info, Trace, Warn, Error} {
+		if v, ok := l.(*loggerPlus); ok {
+			add(v.logger.Writer())
+		}
+	}
+	for _, s := range o.sinks {
+		add(s.w)
+	}
+	return writers
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The buffer which is safe to read while flushing.
+type testSyncBuffer struct {
+	lock sync.Mutex
+	b    bytes.Buffer
+}
+
+func (v *testSyncBuffer) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.b.Write(p)
+}
+
+func (v *testSyncBuffer) String() string {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.b.String()
+}
+
+func TestAutoFlush(t *testing.T) {
+	var f testSyncBuffer
+	Switch(bufio.NewWriter(&f))
+	defer Switch(os.Stdout)
+
+	SetAutoFlush(10 * time.Millisecond)
+	defer SetAutoFlush(0)
+
+	T(nil, "flushed by interval")
+	for i := 0; i < 100 && f.String() == ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if s := f.String(); !strings.HasSuffix(s, "] flushed by interval\n") {
+		t.Errorf("unexpected %q", s)
+	}
+
+	SetAutoFlush(0)
+	T(nil, "flushed by close")
+	Close()
+	if s := f.String(); !strings.HasSuffix(s, "] flushed by close\n") {
+		t.Errorf("unexpected %q", s)
+	}
+}
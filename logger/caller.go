@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Set whether show the caller as file:line, such as file.go:42, default to false.
+// In JSON, it's the caller field.
+// @remark The caller is the first frame out of logger package, so it's correct
+// 	for both logger.T and logger.Trace.Println.
+func SetShowCaller(show bool) {
+	updateOptions(WithShowCaller(show))
+}
+
+// Set whether show the function of caller, such as pkg.Func (file.go:42), default to false.
+// In JSON, it's the func field. It only works when SetShowCaller is enabled.
+// @remark It's more expensive than file:line, to resolve the function name.
+func SetCallerFunc(show bool) {
+	updateOptions(WithCallerFunc(show))
+}
+
+// Set the number of frames to skip for the caller, default to 0 to use the first frame out
+// of logger package, so it's the caller of logger.T, or of logger.Trace.Println, however
+// deep the layers in logger are. Set to the number of layers of wrapper functions of user,
+// to show the caller of the wrappers, for example, 1 for the wrapper:
+//		func logf(ctx logger.Context, format string, a ...interface{}) {
+//			logger.Tf(ctx, "[app] "+format, a...)
+//		}
+// Then the caller is the caller of logf, and 2 if logf is called by another wrapper.
+// @remark The wrappers in the subpackages of logger, such as logrusapi, are never counted.
+// @remark It's not applied to the caller of slog, which is reported by slog.
+func SetCallerSkip(n int) {
+	updateOptions(WithCallerSkip(n))
+}
+
+// The prefix of functions in logger package, such as github.com/cheenwe/learn-go/logger.
+var packagePrefix = func() string {
+	name := runtime.FuncForPC(reflect.ValueOf(SetShowCaller).Pointer()).Name()
+	slash := strings.LastIndex(name, "/")
+	return name[:slash+strings.Index(name[slash+1:], ".")+2]
+}()
+
+// Whether the function is in logger package or its subpackages, such as logrusapi.
+func inLoggerPackage(function string) bool {
+	return strings.HasPrefix(function, packagePrefix) ||
+		strings.HasPrefix(function, strings.TrimSuffix(packagePrefix, ".")+"/")
+}
+
+// The caller file:line, and the function if required.
+// If pc is zero, use the first caller out of logger package and its subpackages,
+// then skip more frames, see SetCallerSkip.
+func callerOf(pc uintptr, function bool, skip int) (caller, fn string) {
+	var frame runtime.Frame
+	if pc != 0 {
+		frame, _ = runtime.CallersFrames([]uintptr{pc}).Next()
+	} else {
+		var pcs [32]uintptr
+		frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+		for {
+			f, more := frames.Next()
+			frame = f
+			if !more {
+				break
+			}
+			if !inLoggerPackage(f.Function) {
+				if skip <= 0 {
+					break
+				}
+				skip--
+			}
+		}
+	}
+
+	if frame.File == "" {
+		return "???:0", ""
+	}
+	caller = filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+
+	if function {
+		fn = frame.Function
+		if slash := strings.LastIndex(fn, "/"); slash >= 0 {
+			fn = fn[slash+1:]
+		}
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	ol "github.com/cheenwe/learn-go/logger"
+)
+
+// The tests are out of logger package, for the frames in it are never the caller.
+
+// The file:line of the next line of the caller of this function.
+func testCallerLine() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%v:%v", file[strings.LastIndex(file, "/")+1:], line+1)
+}
+
+// The helper of user, which wraps logger.
+func testLogHelper(ctx ol.Context, s string) {
+	ol.Tf(ctx, "[helper] %v", s)
+}
+
+// The wrapper of helper.
+func testLogWrapper(ctx ol.Context, s string) {
+	testLogHelper(ctx, s)
+}
+
+func testCallerOf(t *testing.T, skip int, fn func() string) {
+	var console bytes.Buffer
+	ol.Switch(&console)
+	defer ol.Switch(os.Stdout)
+
+	ol.SetShowCaller(true)
+	ol.SetCallerSkip(skip)
+	defer ol.SetShowCaller(false)
+	defer ol.SetCallerSkip(0)
+
+	expect := fn()
+	if s := console.String(); !strings.Contains(s, " "+expect+": ") {
+		t.Errorf("expect caller %v, actual %q", expect, s)
+	}
+}
+
+func TestCallerSkip(t *testing.T) {
+	t.Run("direct", func(t *testing.T) {
+		testCallerOf(t, 0, func() string {
+			expect := testCallerLine()
+			ol.T(nil, "direct")
+			return expect
+		})
+		testCallerOf(t, 0, func() string {
+			expect := testCallerLine()
+			ol.Trace.Println(nil, "direct")
+			return expect
+		})
+	})
+
+	t.Run("helper", func(t *testing.T) {
+		testCallerOf(t, 1, func() string {
+			expect := testCallerLine()
+			testLogHelper(nil, "helper")
+			return expect
+		})
+	})
+
+	t.Run("wrapper", func(t *testing.T) {
+		testCallerOf(t, 2, func() string {
+			expect := testCallerLine()
+			testLogWrapper(nil, "wrapper")
+			return expect
+		})
+	})
+}
@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The log entry, parsed from the written log.
+// For text log, only the level is parsed, while for JSON log, all fields are parsed, see DecodeEntry.
+type Entry struct {
+	// The level, only valid when HasLevel is true.
+	Level    Level
+	HasLevel bool
+	// The raw log line, without the line ending.
+	Raw []byte
+
+	// The time, zero if not present.
+	Time time.Time
+	// The pid, and the cid which is only valid when HasCid is true.
+	Pid    int
+	Cid    int
+	HasCid bool
+	// The id of goroutine, zero if not present.
+	Goroutine uint64
+	// The sequence number, zero if not present, see SetShowSequence.
+	Seq uint64
+	// The delta, topic, extra prefix, caller and function, empty if not present.
+	Delta, Topic, Prefix, Caller, Function string
+	// The tags, nil if not present, see WithTags.
+	Tags []string
+	// The message.
+	Message string
+	// The user fields, where the group is map[string]interface{}, and the number is json.Number.
+	Fields map[string]interface{}
+}
+
+// The writer to capture logs for testing, which parses each line as an Entry, for example:
+//		w := logger.NewCaptureWriter()
+//		logger.Switch(w)
+//		doSomething()
+//		for _, e := range w.Get() {
+//			if e.HasLevel && e.Level >= logger.LevelWarn {
+//				t.Errorf("unexpected log %s", e.Raw)
+//			}
+//		}
+// The level is parsed from the label such as [warn] of text log, or the level field of JSON log.
+// @remark It's safe for concurrent use.
+type CaptureWriter struct {
+	lock    sync.Mutex
+	entries []Entry
+	// The partial line, wait for the line ending.
+	pending []byte
+}
+
+func NewCaptureWriter() *CaptureWriter {
+	return &CaptureWriter{}
+}
+
+func (v *CaptureWriter) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.pending = splitLines(v.pending, p, func(line []byte) {
+		v.entries = append(v.entries, parseEntry(line))
+	})
+	return len(p), nil
+}
+
+// Append p to pending and call fn for each complete line, which is copied and
+// without the line ending, then return the partial line to wait for more.
+func splitLines(pending, p []byte, fn func(line []byte)) []byte {
+	pending = append(pending, p...)
+	for {
+		pos := bytes.IndexByte(pending, '\n')
+		if pos < 0 {
+			return pending
+		}
+
+		line := bytes.TrimSuffix(pending[:pos], []byte("\r"))
+		fn(append([]byte(nil), line...))
+		pending = pending[pos+1:]
+	}
+}
+
+// Get the captured entries, in the order of written.
+func (v *CaptureWriter) Get() []Entry {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return append([]Entry(nil), v.entries...)
+}
+
+// Reset to remove all captured entries.
+func (v *CaptureWriter) Reset() {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.entries, v.pending = nil, nil
+}
+
+// Capture the logs above level until the returned checker is called, for example,
+// to assert the happy path has no warnings or errors:
+//		check := logger.ExpectNoLogsAbove(logger.LevelTrace)
+//		doSomething()
+//		if err := check(); err != nil {
+//			t.Error(err)
+//		}
+// The checker removes the capturing sink, and returns error with the captured logs if any.
+// @remark The logs disabled by SetLevel are not captured.
+func ExpectNoLogsAbove(level Level) func() error {
+	w := NewCaptureWriter()
+	s := &sink{w: w, format: FormatText, level: level + 1}
+	updateOptions(func(o *options) {
+		o.sinks = append(append([]*sink(nil), o.sinks...), s)
+	})
+
+	return func() error {
+		removeSink(s)
+
+		var lines []string
+		for _, e := range w.Get() {
+			if e.HasLevel && e.Level > level {
+				lines = append(lines, string(e.Raw))
+			}
+		}
+		if len(lines) > 0 {
+			return fmt.Errorf("%v logs above %v:\n%v", len(lines), level, strings.Join(lines, "\n"))
+		}
+		return nil
+	}
+}
+
+// Parse the level of line, by label of text log, or all fields of JSON log.
+func parseEntry(line []byte) Entry {
+	e := Entry{Raw: line}
+
+	if bytes.HasPrefix(line, []byte("{")) {
+		if d, err := DecodeEntry(line); err == nil {
+			e = d
+		}
+		return e
+	}
+
+	// Skip the color, such as \033[31m.
+	for bytes.HasPrefix(line, []byte("\033[")) {
+		pos := bytes.IndexByte(line, 'm')
+		if pos < 0 {
+			return e
+		}
+		line = line[pos+1:]
+	}
+
+	if bytes.HasPrefix(line, []byte("[")) {
+		if pos := bytes.IndexByte(line, ']'); pos > 0 {
+			e.Level, e.HasLevel = parseLevel(string(line[1:pos]))
+		}
+	}
+	return e
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// Create a sink which delivers each log as Entry on the channel, for components
+// to react to logs in process, for example, show the last error in UI:
+//		entries, w := logger.NewChannelSink(16)
+//		logger.AddSink(w, logger.FormatJSON, logger.LevelError)
+//		go func() {
+//			for e := range entries {
+//				ui.ShowError(e.Raw)
+//			}
+//		}()
+// The entry is dropped if the channel is full, so a slow consumer never blocks
+// logging, and the dropped ones are counted as dropped of SetHeartbeat.
+// @remark The channel is closed when the writer is closed, for example, by Close.
+func NewChannelSink(buffer int) (<-chan Entry, io.Writer) {
+	w := &channelWriter{entries: make(chan Entry, buffer)}
+	return w.entries, w
+}
+
+// The writer which parses each line as Entry and sends it to channel.
+type channelWriter struct {
+	lock    sync.Mutex
+	entries chan Entry
+	closed  bool
+	// The partial line, wait for the line ending.
+	pending []byte
+}
+
+func (v *channelWriter) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	v.pending = splitLines(v.pending, p, func(line []byte) {
+		select {
+		case v.entries <- parseEntry(line):
+		default:
+			atomic.AddUint64(&stats.dropped, 1)
+			reportf("channel sink of %v is full, drop", cap(v.entries))
+		}
+	})
+	return len(p), nil
+}
+
+func (v *channelWriter) Close() error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if !v.closed {
+		v.closed = true
+		close(v.entries)
+	}
+	return nil
+}
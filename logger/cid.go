@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// The last cid of the default generator.
+var lastCID int64
+
+// The default generator of cid, which increases from 1.
+func nextCID() int {
+	return int(atomic.AddInt64(&lastCID, 1))
+}
+
+// Create a context with a new cid for a connection, which keeps the deadline and values
+// of parent, for example:
+//		ctx := logger.NewContext(context.Background())
+//		logger.T(ctx, "new connection") // [trace] 2006/01/02 15:04:05.000000 [pid][1] new connection
+// The cid is generated by SetCIDGenerator, default to increase from 1. The parent is
+// context.Background if nil.
+func NewContext(parent context.Context) context.Context {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	generate := nextCID
+	if fn := loadOptions().cidGenerator; fn != nil {
+		generate = fn
+	}
+	return &cidValueContext{Context: parent, cid: generate()}
+}
+
+// Set the generator of cid for NewContext, such as snowflake ids or random values, for example:
+//		logger.SetCIDGenerator(func() int {
+//			return int(snowflake.Next())
+//		})
+// Set to nil to restore the default generator, which increases from 1.
+// @remark The generator is called concurrently by NewContext, so it must be safe for
+// 	concurrent use, which is the responsibility of user.
+func SetCIDGenerator(fn func() int) {
+	updateOptions(WithCIDGenerator(fn))
+}
@@ -0,0 +1,468 @@
+package logger
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// The option of logger for Configure, see the With functions.
+type Option func(o *options)
+
+// Apply the options atomically, so no log is written with part of them, for example,
+// to configure the logger in init of main package:
+//		func init() {
+//			logger.Configure(
+//				logger.WithLevel(logger.LevelInfo),
+//				logger.WithFormat(logger.FormatJSON),
+//				logger.WithShowCaller(true),
+//			)
+//		}
+// There is a With option for each Set function of options, such as WithLevel of SetLevel,
+// and the Set function is equivalent to Configure the option. The options are applied in
+// order, so the later one overrides the former.
+// The settings which are not options are only set by their functions:
+//		SetCIDFormat, which validates the format and returns error.
+//		SetHeartbeat and SetAutoFlush, which start the goroutines.
+//		SetFallbackWriter, which receives SIGPIPE.
+//		Switch, EnableWAL and the other functions of the underlayer io.
+// @remark It's safe to call in init of any package, for the logger is initialized before
+// 	the packages which import it, while the logs before Configure use the defaults.
+func Configure(opts ...Option) {
+	var resized, unbuffered bool
+	updateOptions(func(o *options) {
+		ringSize, flushOnError := o.ringSize, o.flushOnError
+		for _, opt := range opts {
+			opt(o)
+		}
+		resized, unbuffered = o.ringSize != ringSize, flushOnError && !o.flushOnError
+	})
+
+	// Discard the kept logs as SetRingSize and SetFlushOnError.
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	if resized {
+		ring.lines, ring.head = nil, 0
+	}
+	if unbuffered {
+		bufferedLines = nil
+		bufferedLRU.Init()
+	}
+}
+
+// The option of SetLevel.
+func WithLevel(level Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// The option of SetFormat.
+func WithFormat(f Format) Option {
+	return func(o *options) {
+		o.format = f
+	}
+}
+
+// The option of SetColorMode.
+func WithColorMode(mode ColorMode) Option {
+	return func(o *options) {
+		o.colorMode = mode
+	}
+}
+
+// The option of SetTimeFormat.
+func WithTimeFormat(layout string) Option {
+	return func(o *options) {
+		o.timeFormat = layout
+	}
+}
+
+// The option of SetTimeFormatISO8601.
+func WithTimeFormatISO8601() Option {
+	return WithTimeFormat(timeFormatISO8601)
+}
+
+// The option of SetTimeZone.
+func WithTimeZone(loc *time.Location) Option {
+	return func(o *options) {
+		o.timeZone = loc
+	}
+}
+
+// The option of SetShowCaller.
+func WithShowCaller(show bool) Option {
+	return func(o *options) {
+		o.showCaller = show
+	}
+}
+
+// The option of SetPrefixSeparator.
+func WithPrefixSeparator(sep string) Option {
+	return func(o *options) {
+		o.prefixSeparator = sep
+	}
+}
+
+// The option of SetGlobalPrefix.
+func WithGlobalPrefix(s string) Option {
+	return func(o *options) {
+		o.globalPrefix = s
+	}
+}
+
+// The option of SetErrorHandler.
+func WithErrorHandler(h func(err error)) Option {
+	return func(o *options) {
+		o.errorHandler = h
+	}
+}
+
+// The option of AddSink.
+func WithSink(w io.Writer, format Format, minLevel Level, opts ...SinkOption) Option {
+	s := newSink(w, format, minLevel, opts)
+	return func(o *options) {
+		o.sinks = append(append([]*sink(nil), o.sinks...), s)
+	}
+}
+
+// The option of SetAsyncPolicy.
+func WithAsyncPolicy(policy AsyncPolicy) Option {
+	return func(o *options) {
+		o.asyncPolicy = policy
+	}
+}
+
+// The option of SetCallerFunc.
+func WithCallerFunc(show bool) Option {
+	return func(o *options) {
+		o.callerFunc = show
+	}
+}
+
+// The option of SetCallerSkip.
+func WithCallerSkip(n int) Option {
+	return func(o *options) {
+		o.callerSkip = n
+	}
+}
+
+// The option of SetCIDGenerator.
+func WithCIDGenerator(fn func() int) Option {
+	return func(o *options) {
+		o.cidGenerator = fn
+	}
+}
+
+// The option of SetPerContextLimit.
+func WithPerContextLimit(max int, window time.Duration) Option {
+	var l *contextLimiter
+	if max > 0 && window > 0 {
+		l = &contextLimiter{max: max, window: window, counts: make(map[int]*contextCount)}
+	}
+
+	return func(o *options) {
+		o.contextLimit = l
+	}
+}
+
+// The option of SetShowDelta.
+func WithShowDelta(show bool) Option {
+	return func(o *options) {
+		o.showDelta = show
+	}
+}
+
+// The option of SetDiagnosticInterval.
+func WithDiagnosticInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.diagInterval = interval
+	}
+}
+
+// The option of SetColumnAlign.
+func WithColumnAlign(align bool) Option {
+	return func(o *options) {
+		o.columnAlign = align
+	}
+}
+
+// The option of SetGlobalPrefixKey.
+func WithGlobalPrefixKey(key string) Option {
+	return func(o *options) {
+		o.globalPrefixKey = key
+	}
+}
+
+// The option of SetPrefixFunc.
+func WithPrefixFunc(fn func(ctx Context) string) Option {
+	return func(o *options) {
+		o.prefixFunc = fn
+	}
+}
+
+// The option of SetLineEnding.
+func WithLineEnding(ending LineEnding) Option {
+	return func(o *options) {
+		o.lineEnding = ending
+	}
+}
+
+// The option of SetEscapeControl.
+func WithEscapeControl(escape bool) Option {
+	return func(o *options) {
+		o.escapeControl = escape
+	}
+}
+
+// The option of SetFlushOnError.
+func WithFlushOnError(enabled bool) Option {
+	return func(o *options) {
+		o.flushOnError = enabled
+	}
+}
+
+// The option of SetFoldPrefix.
+func WithFoldPrefix(fold bool) Option {
+	return func(o *options) {
+		o.foldPrefix = fold
+	}
+}
+
+// The option of SetValueFormatter.
+func WithValueFormatter(text func(v interface{}) (string, bool), json func(v interface{}) ([]byte, bool)) Option {
+	return func(o *options) {
+		o.textFormatter, o.jsonFormatter = text, json
+	}
+}
+
+// The option of SetShowGoroutineID.
+func WithShowGoroutineID(show bool) Option {
+	return func(o *options) {
+		o.showGoroutineID = show
+	}
+}
+
+// The option of SetMeasureOverhead.
+func WithMeasureOverhead(measure bool) Option {
+	return func(o *options) {
+		o.measureOverhead = measure
+	}
+}
+
+// The option of SetHTTPHeaders.
+func WithHTTPHeaders(headers ...string) Option {
+	return func(o *options) {
+		o.httpHeaders = nil
+		for _, h := range headers {
+			o.httpHeaders = append(o.httpHeaders, http.CanonicalHeaderKey(h))
+		}
+	}
+}
+
+// The option of SetHTTPBodyLimit.
+func WithHTTPBodyLimit(n int) Option {
+	return func(o *options) {
+		o.httpBodyLimit = n
+	}
+}
+
+// The option of SetStatusLevel.
+func WithStatusLevel(fn func(status int) Level) Option {
+	return func(o *options) {
+		o.statusLevel = fn
+	}
+}
+
+// The option of SetJSONFieldOrder.
+func WithJSONFieldOrder(order []string) Option {
+	var resolved []string
+	for _, name := range append(append([]string(nil), order...), defaultJSONOrder...) {
+		if !containsString(defaultJSONOrder, name) || containsString(resolved, name) {
+			continue
+		}
+		resolved = append(resolved, name)
+	}
+
+	return func(o *options) {
+		o.jsonOrder = resolved
+	}
+}
+
+// The option of SetJSONStyle.
+func WithJSONStyle(style JSONStyle) Option {
+	return func(o *options) {
+		o.jsonStyle = style
+	}
+}
+
+// The option of SetLevelMatcher.
+func WithLevelMatcher(matcher func(ctx Context) (Level, bool)) Option {
+	return func(o *options) {
+		o.levelMatcher = matcher
+	}
+}
+
+// The option of SetEscalateNearDeadline.
+func WithEscalateNearDeadline(threshold time.Duration) Option {
+	return func(o *options) {
+		o.escalateThreshold = threshold
+	}
+}
+
+// The option of SetColorScope.
+func WithColorScope(scope ColorScope) Option {
+	return func(o *options) {
+		o.colorScope = scope
+	}
+}
+
+// The option of SetErrorIsFatal.
+func WithErrorIsFatal(fatal bool) Option {
+	return func(o *options) {
+		o.errorIsFatal = fatal
+	}
+}
+
+// The option of SetErrorFailFunc.
+func WithErrorFailFunc(fail func(line string)) Option {
+	return func(o *options) {
+		o.errorFail = fail
+	}
+}
+
+// The option of SetMirrorErrorsToStderr.
+func WithMirrorErrorsToStderr(mirror bool) Option {
+	return func(o *options) {
+		o.mirrorErrors = mirror
+	}
+}
+
+// The option of SetMirrorWarnsToStderr.
+func WithMirrorWarnsToStderr(mirror bool) Option {
+	return func(o *options) {
+		o.mirrorWarns = mirror
+	}
+}
+
+// The option of SetRateLimit.
+func WithRateLimit(level Level, burst int, perSecond float64) Option {
+	var l *rateLimiter
+	if burst > 0 && perSecond > 0 {
+		l = &rateLimiter{burst: float64(burst), rate: perSecond, tokens: float64(burst), last: time.Now()}
+	}
+
+	return func(o *options) {
+		if level >= LevelInfo && level <= LevelError {
+			o.rateLimits[level] = l
+		}
+	}
+}
+
+// The option of SetDailyQuota.
+func WithDailyQuota(bytes int64) Option {
+	var q *dailyQuota
+	if bytes > 0 {
+		q = &dailyQuota{limit: bytes}
+	}
+
+	return func(o *options) {
+		o.dailyQuota = q
+	}
+}
+
+// The option of SetRingSize.
+func WithRingSize(size int) Option {
+	return func(o *options) {
+		o.ringSize = size
+	}
+}
+
+// The option of SetCheckFormat.
+func WithCheckFormat(check bool) Option {
+	return func(o *options) {
+		o.checkFormat = check
+	}
+}
+
+// The option of SetTraceSampling.
+func WithTraceSampling(rate float64) Option {
+	return func(o *options) {
+		o.sampleRate = rate
+	}
+}
+
+// The option of SetShowSequence.
+func WithShowSequence(show bool) Option {
+	return func(o *options) {
+		o.showSequence = show
+	}
+}
+
+// The option of SetSQLArgs.
+func WithSQLArgs(enabled bool) Option {
+	return func(o *options) {
+		o.sqlArgs = enabled
+	}
+}
+
+// The option of SetSQLSlowThreshold.
+func WithSQLSlowThreshold(threshold time.Duration) Option {
+	return func(o *options) {
+		o.sqlSlowThreshold = threshold
+	}
+}
+
+// The option of SetJSONStreamStyle.
+func WithJSONStreamStyle(style JSONStreamStyle) Option {
+	return func(o *options) {
+		o.jsonStream = style
+	}
+}
+
+// The option of SetStrictConcurrency.
+func WithStrictConcurrency(strict bool) Option {
+	return func(o *options) {
+		o.strictConcurrency = strict
+	}
+}
+
+// The option of SetSummaryLevel.
+func WithSummaryLevel(level Level) Option {
+	return func(o *options) {
+		o.summaryLevel = level
+	}
+}
+
+// The option of SetTimeMode.
+func WithTimeMode(mode TimeMode) Option {
+	return func(o *options) {
+		o.timeMode = mode
+	}
+}
+
+// The option of SetTopicLevel.
+func WithTopicLevel(name string, level Level) Option {
+	return func(o *options) {
+		levels := make(map[string]Level)
+		for k, v := range o.topicLevels {
+			levels[k] = v
+		}
+		levels[name] = level
+		o.topicLevels = levels
+	}
+}
+
+// The option of SetVerbose.
+func WithVerbose(enabled bool) Option {
+	return func(o *options) {
+		o.verbose = enabled
+	}
+}
+
+// The option of SetShowVersion.
+func WithShowVersion(show bool) Option {
+	return func(o *options) {
+		o.showVersion = show
+	}
+}
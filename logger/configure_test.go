@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConfigure(t *testing.T) {
+	var buf bytes.Buffer
+	Switch(&buf)
+	defer Switch(os.Stdout)
+
+	Configure(
+		WithFlushOnError(true),
+		WithRingSize(10),
+		WithGlobalPrefix("app"),
+		WithRateLimit(LevelWarn, 1, 1),
+		WithJSONFieldOrder([]string{"msg"}),
+	)
+	defer Configure(
+		WithFlushOnError(false),
+		WithRingSize(0),
+		WithGlobalPrefix(""),
+		WithRateLimit(LevelWarn, 0, 0),
+		WithJSONFieldOrder(nil),
+	)
+
+	o := loadOptions()
+	if !o.flushOnError || o.ringSize != 10 || o.globalPrefix != "app" || o.rateLimits[LevelWarn] == nil ||
+		o.jsonOrder[0] != "msg" {
+		t.Errorf("unexpected options %+v", o)
+	}
+
+	// The buffered logs are discarded when disabled, as SetFlushOnError.
+	T(testCidContext(100), "buffered")
+	Configure(WithFlushOnError(false))
+	E(testCidContext(100), "failed")
+	if s := buf.String(); strings.Contains(s, "buffered") || !strings.Contains(s, "failed") {
+		t.Errorf("unexpected %q", s)
+	}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The limit of logs for each context in window.
+type contextLimiter struct {
+	max    int
+	window time.Duration
+
+	lock   sync.Mutex
+	counts map[int]*contextCount
+	// The last time to remove the expired counts.
+	cleaned time.Time
+}
+
+// The count of logs for a context in current window.
+type contextCount struct {
+	start time.Time
+	n     int
+	// Whether warned the context is too chatty in current window.
+	warned bool
+}
+
+// Set the limit of logs for each context, allow up to max logs in window, and drop the rest,
+// for example, to avoid a runaway connection flooding logs:
+//		logger.SetPerContextLimit(1000, time.Minute)
+// The first dropped log of context in window is replaced by a warning, for example:
+//		[warn] 2006/01/02 15:04:05.000000 [pid][cid] this connection is too chatty max=1000 window=1m0s
+// The logs are matched by cid, and the logs without cid are not limited.
+// Set max or window to 0 to remove the limit, which is the default.
+// @remark The dropped logs are also counted as dropped of SetHeartbeat.
+func SetPerContextLimit(max int, window time.Duration) {
+	updateOptions(WithPerContextLimit(max, window))
+}
+
+// Count a log of ctx at now, return whether allowed, and whether to warn if not allowed.
+func (v *contextLimiter) allow(ctx Context, now time.Time) (ok, warn bool) {
+	cid, ok := cidOf(ctx)
+	if !ok {
+		return true, false
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if now.Sub(v.cleaned) >= v.window {
+		for k, c := range v.counts {
+			if now.Sub(c.start) >= v.window {
+				delete(v.counts, k)
+			}
+		}
+		v.cleaned = now
+	}
+
+	c := v.counts[cid]
+	if c == nil || now.Sub(c.start) >= v.window {
+		c = &contextCount{start: now}
+		v.counts[cid] = c
+	}
+
+	if c.n < v.max {
+		c.n++
+		return true, false
+	}
+
+	atomic.AddUint64(&stats.dropped, 1)
+	warn = !c.warned
+	c.warned = true
+	return false, warn
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// The shards of counters, to reduce the contention of Counts and Write.
+const countingShards = 16
+
+// The sink to count logs by key, for operators to find the most frequent logs, for example:
+//		s := logger.NewCountingSink(func(e logger.Entry) string {
+//			return e.Message
+//		})
+//		logger.AddSink(s, logger.FormatJSON, logger.LevelWarn)
+//		......
+//		for key, n := range s.Counts() {
+//			fmt.Println(n, key)
+//		}
+// The line is parsed as Entry, so add it with FormatJSON to get all fields, see DecodeEntry.
+// The entry is ignored if keyFn returns empty.
+// @remark It's safe for concurrent use, and keyFn is called concurrently.
+type CountingSink struct {
+	keyFn  func(Entry) string
+	shards [countingShards]countingShard
+
+	// Protect the pending only.
+	lock sync.Mutex
+	// The partial line, wait for the line ending.
+	pending []byte
+}
+
+type countingShard struct {
+	lock   sync.Mutex
+	counts map[string]int64
+}
+
+func NewCountingSink(keyFn func(Entry) string) *CountingSink {
+	return &CountingSink{keyFn: keyFn}
+}
+
+// Only split the lines under lock, then parse and count them by the lock of shard.
+func (v *CountingSink) Write(p []byte) (int, error) {
+	var lines [][]byte
+	v.lock.Lock()
+	v.pending = splitLines(v.pending, p, func(line []byte) {
+		lines = append(lines, line)
+	})
+	v.lock.Unlock()
+
+	for _, line := range lines {
+		if key := v.keyFn(parseEntry(line)); key != "" {
+			v.shardOf(key).add(key)
+		}
+	}
+	return len(p), nil
+}
+
+func (v *CountingSink) shardOf(key string) *countingShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &v.shards[h.Sum32()%countingShards]
+}
+
+func (v *countingShard) add(key string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.counts == nil {
+		v.counts = make(map[string]int64)
+	}
+	v.counts[key]++
+}
+
+// The counts of each key, which is a copy.
+func (v *CountingSink) Counts() map[string]int64 {
+	counts := make(map[string]int64)
+	for i := range v.shards {
+		s := &v.shards[i]
+		s.lock.Lock()
+		for k, n := range s.counts {
+			counts[k] = n
+		}
+		s.lock.Unlock()
+	}
+	return counts
+}
+
+// Reset to remove all counts.
+func (v *CountingSink) Reset() {
+	for i := range v.shards {
+		s := &v.shards[i]
+		s.lock.Lock()
+		s.counts = nil
+		s.lock.Unlock()
+	}
+}
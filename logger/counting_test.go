@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCountingSinkConcurrent(t *testing.T) {
+	// The keyFn of two writes runs concurrently, or it times out.
+	var once sync.Once
+	first, second := make(chan struct{}), make(chan struct{})
+	s := NewCountingSink(func(e Entry) string {
+		if e.Message == "slow" {
+			once.Do(func() { close(first) })
+			select {
+			case <-second:
+			case <-time.After(3 * time.Second):
+				return "timeout"
+			}
+		}
+		return e.Message
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Write([]byte("{\"msg\":\"slow\"}\n"))
+	}()
+
+	<-first
+	for i := 0; i < 100; i++ {
+		s.Write([]byte(fmt.Sprintf("{\"msg\":\"key%v\"}\n{\"msg\":\"all\"}\n", i%2)))
+	}
+	close(second)
+	wg.Wait()
+
+	counts := s.Counts()
+	if counts["slow"] != 1 || counts["timeout"] != 0 || counts["key0"] != 50 || counts["key1"] != 50 || counts["all"] != 100 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// The sink to record the distinct messages, for fuzz or tests to assert the coverage
+// of log branches, for example:
+//		s := logger.NewCoverageSink()
+//		logger.AddSink(s, logger.FormatJSON, logger.LevelInfo)
+//		fuzzSomething()
+//		if !containsMessage(s.Messages(), "invalid header") {
+//			t.Error("the branch of invalid header is not covered")
+//		}
+// The message is the msg field of JSON log, so add it with FormatJSON, otherwise
+// the whole line is recorded, which is rarely distinct for the timestamp.
+// @remark It's safe for concurrent use.
+type CoverageSink struct {
+	lock     sync.Mutex
+	messages map[string]bool
+	// The partial line, wait for the line ending.
+	pending []byte
+}
+
+func NewCoverageSink() *CoverageSink {
+	return &CoverageSink{messages: make(map[string]bool)}
+}
+
+func (v *CoverageSink) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.pending = splitLines(v.pending, p, func(line []byte) {
+		var obj struct {
+			Msg *string `json:"msg"`
+		}
+		if json.Unmarshal(line, &obj) == nil && obj.Msg != nil {
+			v.messages[*obj.Msg] = true
+		} else {
+			v.messages[string(line)] = true
+		}
+	})
+	return len(p), nil
+}
+
+// The distinct messages, sorted.
+func (v *CoverageSink) Messages() []string {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	messages := make([]string, 0, len(v.messages))
+	for m := range v.messages {
+		messages = append(messages, m)
+	}
+	sort.Strings(messages)
+	return messages
+}
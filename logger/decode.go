@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Decode a line of JSON log written by this logger, for log viewers and tests, for example:
+//		e, err := logger.DecodeEntry(line)
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(e.Time, e.Level, e.Cid, e.Message, e.Fields["key"])
+// Both StyleDefault and StyleECS are decoded, and the fields which are not built-in are put
+// into Fields, such as the global prefix and the constant fields.
+// @remark The line ending, and the comma or bracket of StyleArray, are trimmed.
+func DecodeEntry(line []byte) (Entry, error) {
+	e := Entry{Raw: line}
+
+	line = bytes.TrimSpace(line)
+	line = bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSuffix(line, []byte(",")), []byte("[")))
+	line = bytes.TrimSuffix(line, []byte("]"))
+
+	d := json.NewDecoder(bytes.NewReader(line))
+	d.UseNumber()
+
+	var obj map[string]interface{}
+	if err := d.Decode(&obj); err != nil {
+		return e, err
+	}
+
+	var file, fileLine string
+	for k, value := range obj {
+		s, _ := value.(string)
+		n, _ := value.(json.Number)
+		switch k {
+		case "ts", "@timestamp":
+			e.Time, _ = time.Parse(time.RFC3339Nano, s)
+		case "level", "log.level":
+			e.Level, e.HasLevel = parseLevel(s)
+		case "pid", "process.pid":
+			e.Pid, _ = strconv.Atoi(n.String())
+		case "seq", "event.sequence":
+			e.Seq, _ = strconv.ParseUint(n.String(), 10, 64)
+		case "cid":
+			if cid, err := strconv.Atoi(n.String()); err == nil {
+				e.Cid, e.HasCid = cid, true
+			}
+		case "goroutine":
+			e.Goroutine, _ = strconv.ParseUint(n.String(), 10, 64)
+		case "delta":
+			e.Delta = s
+		case "topic":
+			e.Topic = s
+		case "prefix":
+			e.Prefix = s
+		case "tags":
+			if tags, ok := value.([]interface{}); ok {
+				for _, tag := range tags {
+					if tag, ok := tag.(string); ok {
+						e.Tags = append(e.Tags, tag)
+					}
+				}
+			}
+		case "caller":
+			e.Caller = s
+		case "log.origin.file.name":
+			file = s
+		case "log.origin.file.line":
+			fileLine = n.String()
+		case "func", "log.origin.function":
+			e.Function = s
+		case "msg", "message":
+			e.Message = s
+		case "ecs.version":
+		default:
+			if e.Fields == nil {
+				e.Fields = make(map[string]interface{})
+			}
+			e.Fields[k] = value
+		}
+	}
+
+	if file != "" {
+		e.Caller = file
+		if fileLine != "" {
+			e.Caller += ":" + fileLine
+		}
+	}
+	return e, nil
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"strconv"
+	"time"
+)
+
+// The max contexts to track the last log, all are reset when exceed.
+const maxDeltaContexts = 10000
+
+// The time of last log of each cid, and of logs without cid, protected by outputLock.
+var deltaLasts map[int]time.Time
+var deltaLast time.Time
+
+// Set whether show the delta since the previous log of the same context, such as +1.234ms,
+// default to false, to eyeball the timing without computing it, for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] +0 start
+//		[trace] 2006/01/02 15:04:05.001234 [pid][cid] +1.234ms done
+// The first log of context shows +0. The logs are matched by cid, while all logs without
+// cid are tracked as one context. In JSON, it's the delta field.
+// @remark The delta is by monotonic clock, so it's correct even the wall clock is changed.
+func SetShowDelta(show bool) {
+	updateOptions(WithShowDelta(show))
+}
+
+// The delta since the last log of ctx, and update the last to t.
+// @remark The outputLock must be held.
+func deltaOf(ctx Context, t time.Time) string {
+	var last time.Time
+	if cid, ok := cidOf(ctx); ok {
+		if deltaLasts == nil || len(deltaLasts) >= maxDeltaContexts {
+			deltaLasts = make(map[int]time.Time)
+		}
+		last, deltaLasts[cid] = deltaLasts[cid], t
+	} else {
+		last, deltaLast = deltaLast, t
+	}
+
+	if last.IsZero() {
+		return "+0"
+	}
+	ms := float64(t.Sub(last)) / float64(time.Millisecond)
+	return "+" + strconv.FormatFloat(ms, 'f', 3, 64) + "ms"
+}
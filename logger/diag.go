@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// The state of diagnostic reporter, to throttle the reports.
+var diag struct {
+	lock       sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+// Set the min interval of diagnostics about the logger itself, default to 5s, which
+// reports the problems such as write errors, full queues and failed reconnects to stderr,
+// at most one in interval, while the others are counted as suppressed, for example:
+//		logger: write failed, err is broken pipe (suppressed 12)
+// Set to a negative interval to disable it.
+// @remark It's independent of the underlayer io and sinks, which might be broken.
+func SetDiagnosticInterval(interval time.Duration) {
+	updateOptions(WithDiagnosticInterval(interval))
+}
+
+// Report the problem of logger to stderr, throttled by SetDiagnosticInterval.
+func reportf(format string, a ...interface{}) {
+	interval := loadOptions().diagInterval
+	if interval < 0 {
+		return
+	}
+
+	diag.lock.Lock()
+	now := time.Now()
+	if !diag.last.IsZero() && now.Sub(diag.last) < interval {
+		diag.suppressed++
+		diag.lock.Unlock()
+		return
+	}
+	suppressed := diag.suppressed
+	diag.last, diag.suppressed = now, 0
+	diag.lock.Unlock()
+
+	msg := fmt.Sprintf(format, a...)
+	if suppressed > 0 {
+		msg = fmt.Sprintf("%v (suppressed %v)", msg, suppressed)
+	}
+	fmt.Fprintf(os.Stderr, "logger: %v\n", msg)
+}
+
+// Handle the error of writing log, by the handler of SetErrorHandler, or report it.
+func handleError(o *options, err error) {
+	if o.errorHandler != nil {
+		o.errorHandler(err)
+		return
+	}
+	reportf("write failed, err is %v", err)
+}
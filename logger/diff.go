@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The max changes and max length of value in LogDiff, to cap the size of log.
+const maxDiffChanges, maxDiffValue = 32, 64
+
+// Log the changes from before to after at level, for example, to audit the config change:
+//		logger.LogDiff(ctx, logger.LevelTrace, "config", oldConfig, newConfig)
+// Then the log is like:
+//		config changed: Port: 80 -> 8080; Upstream.Host: a.com -> b.com; Tags[env]: <none> -> prod
+// The structs are compared by exported fields, and maps by keys, recursively, while
+// other values such as slices are compared as a whole, so are the structs with Equal
+// method or without exported fields, such as time.Time. Nothing is logged if no change.
+// @remark The unexported fields of other structs are ignored, the cyclic pointers are
+// 	compared once, and the changes and values are truncated if too many or too long.
+func LogDiff(ctx Context, level Level, label string, before, after interface{}) {
+	changes := diffChanges(before, after)
+	if len(changes) == 0 {
+		return
+	}
+
+	if len(changes) > maxDiffChanges {
+		changes = append(changes[:maxDiffChanges], fmt.Sprintf("... %v more", len(changes)-maxDiffChanges))
+	}
+	printEntry(levelLogger(level), &entry{ctx: ctx, message: label + " changed: " + strings.Join(changes, "; ")})
+}
+
+// The changes from a to b, such as "Port: 80 -> 8080".
+func diffChanges(a, b interface{}) []string {
+	var changes []string
+	diffValues("", reflect.ValueOf(a), reflect.ValueOf(b), &changes, make(map[diffVisit]bool))
+	return changes
+}
+
+// The pair of pointers which are compared, to stop at cycles.
+type diffVisit struct {
+	a, b uintptr
+	t    reflect.Type
+}
+
+func diffValues(path string, a, b reflect.Value, changes *[]string, visited map[diffVisit]bool) {
+	change := func() {
+		name := path
+		if name == "" {
+			name = "value"
+		}
+		*changes = append(*changes, fmt.Sprintf("%v: %v -> %v", name, diffValue(a), diffValue(b)))
+	}
+
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		if a.IsValid() != b.IsValid() || a.IsValid() && !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			change()
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				change()
+			}
+			return
+		}
+		if a.Kind() == reflect.Ptr {
+			visit := diffVisit{a.Pointer(), b.Pointer(), a.Type()}
+			if visited[visit] {
+				return
+			}
+			visited[visit] = true
+		}
+		diffValues(path, a.Elem(), b.Elem(), changes, visited)
+	case reflect.Struct:
+		if equal, ok := diffEqual(a, b); ok {
+			if !equal {
+				change()
+			}
+			return
+		}
+		for i := 0; i < a.NumField(); i++ {
+			if f := a.Type().Field(i); f.PkgPath == "" {
+				diffValues(joinDiffPath(path, f.Name), a.Field(i), b.Field(i), changes, visited)
+			}
+		}
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			k := keys[name]
+			diffValues(path+"["+name+"]", a.MapIndex(k), b.MapIndex(k), changes, visited)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			change()
+		}
+	}
+}
+
+// Compare the structs as a whole, by the Equal method such as time.Time, or by
+// reflect.DeepEqual if no exported fields, false if compare by fields.
+func diffEqual(a, b reflect.Value) (equal, ok bool) {
+	if m := a.MethodByName("Equal"); m.IsValid() {
+		if t := m.Type(); t.NumIn() == 1 && t.In(0) == a.Type() && t.NumOut() == 1 && t.Out(0).Kind() == reflect.Bool {
+			return m.Call([]reflect.Value{b})[0].Bool(), true
+		}
+	}
+
+	for i := 0; i < a.NumField(); i++ {
+		if a.Type().Field(i).PkgPath == "" {
+			return false, false
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface()), true
+}
+
+func joinDiffPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// The value in diff, <none> for missing value, and truncated if too long.
+func diffValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<none>"
+	}
+
+	s := fmt.Sprint(v.Interface())
+	if len(s) > maxDiffValue {
+		s = s[:maxDiffValue] + "..."
+	}
+	return s
+}
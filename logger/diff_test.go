@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffChanges(t *testing.T) {
+	type upstream struct {
+		Host string
+		port int
+	}
+	type config struct {
+		Port     int
+		Upstream *upstream
+		Tags     map[string]string
+		Names    []string
+	}
+
+	before := config{Port: 80, Upstream: &upstream{"a.com", 1}, Tags: map[string]string{"app": "x"}, Names: []string{"a"}}
+	after := config{Port: 8080, Upstream: &upstream{"b.com", 2}, Tags: map[string]string{"app": "x", "env": "prod"}, Names: []string{"a"}}
+
+	expect := []string{
+		"Port: 80 -> 8080",
+		"Upstream.Host: a.com -> b.com",
+		"Tags[env]: <none> -> prod",
+	}
+	if changes := diffChanges(before, after); !reflect.DeepEqual(changes, expect) {
+		t.Errorf("expect %v, actual %v", expect, changes)
+	}
+
+	if changes := diffChanges(nil, 1); !reflect.DeepEqual(changes, []string{"value: <none> -> 1"}) {
+		t.Errorf("invalid nil diff %v", changes)
+	}
+	if changes := diffChanges(before, before); len(changes) != 0 {
+		t.Errorf("expect no change, actual %v", changes)
+	}
+}
+
+func TestDiffChangesOpaque(t *testing.T) {
+	type job struct {
+		Name  string
+		Start time.Time
+	}
+
+	t0 := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	before, after := job{"a", t0}, job{"a", t0.Add(time.Second)}
+	if changes := diffChanges(before, after); len(changes) != 1 || changes[0][:7] != "Start: " {
+		t.Errorf("expect change of Start, actual %v", changes)
+	}
+	if changes := diffChanges(before, job{"a", t0.In(time.FixedZone("MST", -7*3600))}); len(changes) != 0 {
+		t.Errorf("expect equal time, actual %v", changes)
+	}
+}
+
+func TestDiffChangesCycle(t *testing.T) {
+	type node struct {
+		Value int
+		Next  *node
+	}
+
+	a, b := &node{Value: 1}, &node{Value: 2}
+	a.Next, b.Next = a, b
+	if changes := diffChanges(a, b); !reflect.DeepEqual(changes, []string{"Value: 1 -> 2"}) {
+		t.Errorf("unexpected changes %v", changes)
+	}
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Write the effective configuration in human-readable form, for support tickets or an
+// admin endpoint, for example:
+//		http.HandleFunc("/logger/config", func(w http.ResponseWriter, r *http.Request) {
+//			logger.DumpConfig(w)
+//		})
+// Then the output is like:
+//		level: trace
+//		format: text
+//		color: auto
+//		sinks: 1
+//		  *os.File format=json level=warn
+//		......
+// @remark The writers are shown by type only, and the functions by whether set, so no
+// 	secret such as the address or token of writer is exposed.
+func DumpConfig(w io.Writer) error {
+	o := loadOptions()
+
+	var lines []string
+	add := func(key string, value interface{}) {
+		lines = append(lines, fmt.Sprintf("%v: %v", key, value))
+	}
+
+	add("level", o.level)
+	if len(o.topicLevels) > 0 {
+		var topics []string
+		for topic, level := range o.topicLevels {
+			topics = append(topics, topic+"="+level.String())
+		}
+		sort.Strings(topics)
+		add("topic levels", strings.Join(topics, " "))
+	}
+	add("format", formatName(o.format))
+	add("color", colorModeName(o.colorMode))
+	add("time", timeName(o))
+	add("sampling", o.sampleRate)
+	for level, l := range o.rateLimits {
+		if l != nil {
+			add("rate limit "+Level(level).String(), fmt.Sprintf("burst=%v rate=%v/s", l.burst, l.rate))
+		}
+	}
+	if l := o.contextLimit; l != nil {
+		add("context limit", fmt.Sprintf("%v per %v", l.max, l.window))
+	}
+	if q := o.dailyQuota; q != nil {
+		add("daily quota", q.limit)
+	}
+	add("caller", o.showCaller)
+	add("goroutine", o.showGoroutineID)
+	add("sequence", o.showSequence)
+	add("delta", o.showDelta)
+	add("ring", o.ringSize)
+	add("constant fields", len(o.constantFields))
+	add("hooks", countHooks(o))
+
+	add("sinks", len(o.sinks))
+	for _, s := range o.sinks {
+		lines = append(lines, fmt.Sprintf("  %T format=%v level=%v time=%v", s.w, formatName(s.format), s.level, timeName(s.optionsOf(o))))
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func formatName(f Format) string {
+	switch f {
+	case FormatJSON:
+		return "json"
+	case FormatProtobuf:
+		return "protobuf"
+	default:
+		return "text"
+	}
+}
+
+func colorModeName(mode ColorMode) string {
+	switch mode {
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	default:
+		return "auto"
+	}
+}
+
+// The time of text log, such as uptime, or the layout and timezone.
+func timeName(o *options) string {
+	if o.timeMode == ModeUptime {
+		return "uptime"
+	}
+
+	s := "default"
+	if o.timeFormat != "" {
+		s = o.timeFormat
+	}
+	if o.timeZone != nil {
+		s += " " + o.timeZone.String()
+	}
+	return s
+}
+
+// The number of hook functions which are set.
+func countHooks(o *options) int {
+	var n int
+	for _, set := range []bool{
+		o.errorHandler != nil, o.levelMatcher != nil, o.prefixFunc != nil, o.cidGenerator != nil,
+		o.textFormatter != nil, o.jsonFormatter != nil, o.errorFail != nil, o.statusLevel != nil,
+	} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,261 @@
+// The echolog package adapts logger to echo, which is separated from logger to keep
+// the echo dependency optional, for example:
+//		e := echo.New()
+//		e.Logger = echolog.New()
+//		e.Use(echolog.Middleware())
+// Then each request has its own cid, and the logs of the request carry it:
+//		logger.T(c.Request().Context(), "handle request")
+// The access log is written by logger.LogStatus after the handler, for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] GET /api 1.5ms status=200
+// The levels of echo are mapped to logger as DEBUG to Info, INFO to Trace,
+// while WARN and ERROR are the same, see logger.Level.
+package echolog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cheenwe/learn-go/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// Create the middleware which binds a new cid to the request by logger.NewContext,
+// and logs the method, path and duration with status by logger.LogStatus,
+// so the level is decided by status, see logger.SetStatusLevel.
+// @remark The status of error returned by handler is from echo.HTTPError, or 500 for others.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r := c.Request()
+			ctx := logger.NewContext(r.Context())
+			c.SetRequest(r.WithContext(ctx))
+
+			start := time.Now()
+			err := next(c)
+
+			status := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok {
+				status = he.Code
+			} else if err != nil {
+				status = 500
+			}
+			logger.LogStatus(ctx, status, fmt.Sprintf("%v %v %v", r.Method, r.URL.Path, time.Since(start)))
+			return err
+		}
+	}
+}
+
+// The logger for echo.Logger, which logs by logger without cid,
+// for the echo.Logger has no context.
+type Logger struct {
+	lock   sync.RWMutex
+	prefix string
+	level  log.Lvl
+}
+
+var _ echo.Logger = (*Logger)(nil)
+
+// Create the logger for echo.Logger, which logs all levels of echo, and the
+// level of logger decides, see logger.SetLevel.
+func New() *Logger {
+	return &Logger{level: log.DEBUG}
+}
+
+// The writer which logs each line at Trace level, for the middlewares of echo
+// such as middleware.LoggerWithConfig.
+func (v *Logger) Output() io.Writer {
+	return &lineWriter{v: v}
+}
+
+// Ignored, for the logs are written by logger, see logger.Switch.
+func (v *Logger) SetOutput(w io.Writer) {
+}
+
+func (v *Logger) Prefix() string {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.prefix
+}
+
+// Set the prefix, which is written as logger.PushPrefix, such as [pid][prefix].
+func (v *Logger) SetPrefix(p string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.prefix = p
+}
+
+func (v *Logger) Level() log.Lvl {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return v.level
+}
+
+// Set the level of echo, the logs below it are dropped before logger.
+func (v *Logger) SetLevel(lvl log.Lvl) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.level = lvl
+}
+
+// Ignored, for the header is decided by logger, see logger.SetFormat.
+func (v *Logger) SetHeader(h string) {
+}
+
+// Log at Trace level of logger, ignore the level of echo.
+func (v *Logger) Print(i ...interface{}) {
+	logger.Tkv(v.context(), fmt.Sprint(i...))
+}
+
+func (v *Logger) Printf(format string, args ...interface{}) {
+	logger.Tkv(v.context(), fmt.Sprintf(format, args...))
+}
+
+func (v *Logger) Printj(j log.JSON) {
+	logger.Tkv(v.context(), "", kvOf(j)...)
+}
+
+func (v *Logger) Debug(i ...interface{}) {
+	v.log(log.DEBUG, fmt.Sprint(i...), nil)
+}
+
+func (v *Logger) Debugf(format string, args ...interface{}) {
+	v.log(log.DEBUG, fmt.Sprintf(format, args...), nil)
+}
+
+func (v *Logger) Debugj(j log.JSON) {
+	v.log(log.DEBUG, "", kvOf(j))
+}
+
+func (v *Logger) Info(i ...interface{}) {
+	v.log(log.INFO, fmt.Sprint(i...), nil)
+}
+
+func (v *Logger) Infof(format string, args ...interface{}) {
+	v.log(log.INFO, fmt.Sprintf(format, args...), nil)
+}
+
+func (v *Logger) Infoj(j log.JSON) {
+	v.log(log.INFO, "", kvOf(j))
+}
+
+func (v *Logger) Warn(i ...interface{}) {
+	v.log(log.WARN, fmt.Sprint(i...), nil)
+}
+
+func (v *Logger) Warnf(format string, args ...interface{}) {
+	v.log(log.WARN, fmt.Sprintf(format, args...), nil)
+}
+
+func (v *Logger) Warnj(j log.JSON) {
+	v.log(log.WARN, "", kvOf(j))
+}
+
+func (v *Logger) Error(i ...interface{}) {
+	v.log(log.ERROR, fmt.Sprint(i...), nil)
+}
+
+func (v *Logger) Errorf(format string, args ...interface{}) {
+	v.log(log.ERROR, fmt.Sprintf(format, args...), nil)
+}
+
+func (v *Logger) Errorj(j log.JSON) {
+	v.log(log.ERROR, "", kvOf(j))
+}
+
+// Log at Error level, then close logger and exit with code 1.
+func (v *Logger) Fatal(i ...interface{}) {
+	v.fatal(fmt.Sprint(i...), nil)
+}
+
+func (v *Logger) Fatalf(format string, args ...interface{}) {
+	v.fatal(fmt.Sprintf(format, args...), nil)
+}
+
+func (v *Logger) Fatalj(j log.JSON) {
+	v.fatal("", kvOf(j))
+}
+
+// Log at Error level, then panic with the message.
+func (v *Logger) Panic(i ...interface{}) {
+	msg := fmt.Sprint(i...)
+	logger.Ekv(v.context(), msg)
+	panic(msg)
+}
+
+func (v *Logger) Panicf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	logger.Ekv(v.context(), msg)
+	panic(msg)
+}
+
+func (v *Logger) Panicj(j log.JSON) {
+	logger.Ekv(v.context(), "", kvOf(j)...)
+	panic(j)
+}
+
+func (v *Logger) log(lvl log.Lvl, msg string, kv []interface{}) {
+	if lvl < v.Level() {
+		return
+	}
+
+	ctx := v.context()
+	switch lvl {
+	case log.DEBUG:
+		logger.Ikv(ctx, msg, kv...)
+	case log.INFO:
+		logger.Tkv(ctx, msg, kv...)
+	case log.WARN:
+		logger.Wkv(ctx, msg, kv...)
+	default:
+		logger.Ekv(ctx, msg, kv...)
+	}
+}
+
+func (v *Logger) fatal(msg string, kv []interface{}) {
+	logger.Ekv(v.context(), msg, kv...)
+	logger.Close()
+	os.Exit(1)
+}
+
+// The context with prefix, nil if no prefix.
+func (v *Logger) context() logger.Context {
+	if p := v.Prefix(); p != "" {
+		return logger.PushPrefix(nil, p)
+	}
+	return nil
+}
+
+// The fields of j in key-value pairs, sorted by key.
+func kvOf(j log.JSON) []interface{} {
+	keys := make([]string, 0, len(j))
+	for k := range j {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kv := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		kv = append(kv, k, j[k])
+	}
+	return kv
+}
+
+type lineWriter struct {
+	v *Logger
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	ctx := w.v.context()
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if line = bytes.TrimRight(line, "\r"); len(line) > 0 {
+			logger.Trace.Printf(ctx, "%s", line)
+		}
+	}
+	return len(p), nil
+}
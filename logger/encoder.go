@@ -0,0 +1,412 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The format of log.
+type Format int
+
+const (
+	// The human-readable text log, the default format.
+	FormatText Format = iota
+	// The JSON log, one object per line.
+	FormatJSON
+	// The binary log, one protobuf frame per entry, see ProtoEntry.
+	FormatProtobuf
+)
+
+// Set the separator between the [pid][cid] prefix and message in text log, default to a space,
+// for example, a tab for column-based tools such as awk:
+//		logger.SetPrefixSeparator("\t")
+// @remark It's not used by JSON and protobuf log, which have no prefix.
+func SetPrefixSeparator(sep string) {
+	updateOptions(WithPrefixSeparator(sep))
+}
+
+// The width of [pid][cid] prefix when align columns, for pid of 7 digits and cid of 8 digits.
+const columnPrefixWidth = len("[1234567][12345678]")
+
+// Set whether align the columns of text log, default to false, to scan logs in terminal,
+// then the level label and the [pid][cid] prefix are padded to fixed widths:
+//		[warn]  2006/01/02 15:04:05.000000 [12][1]             message
+//		[trace] 2006/01/02 15:04:05.000000 [12345][100]        message
+// @remark It's not used by JSON and protobuf log, and the color is not padded.
+func SetColumnAlign(align bool) {
+	updateOptions(WithColumnAlign(align))
+}
+
+// Set the global prefix of all logs, default to empty, which is written before the
+// [pid][cid] prefix, for example, the name of cluster:
+//		logger.SetGlobalPrefix("[cluster-a]")
+// Then the log is "[trace] 2006/01/02 15:04:05.000000 [cluster-a][pid][cid] message".
+// In JSON, it's a field, see SetGlobalPrefixKey.
+func SetGlobalPrefix(s string) {
+	updateOptions(WithGlobalPrefix(s))
+}
+
+// Set the key of global prefix in JSON log, default to global_prefix.
+func SetGlobalPrefixKey(key string) {
+	updateOptions(WithGlobalPrefixKey(key))
+}
+
+// Set the function to build extra prefix from context, which is written after the
+// [pid][cid] prefix, for example, to add the user id:
+//		logger.SetPrefixFunc(func(ctx logger.Context) string {
+//			if u, ok := ctx.(userContext); ok {
+//				return fmt.Sprintf("[user=%v]", u.UserID())
+//			}
+//			return ""
+//		})
+// Then the log is "[pid][cid][user=42] message". In JSON, it's the prefix field.
+// Set to nil to remove it, which is the default.
+// @remark The function is called once for each log, so it should be fast.
+func SetPrefixFunc(fn func(ctx Context) string) {
+	updateOptions(WithPrefixFunc(fn))
+}
+
+// Set the format of log, FormatText, FormatJSON or FormatProtobuf.
+func SetFormat(f Format) {
+	updateOptions(WithFormat(f))
+}
+
+// The entry of log, a message with optional structured fields.
+type entry struct {
+	time    time.Time
+	ctx     Context
+	message string
+	fields  []field
+	// The format and args of message, formatted by Sprintln if println,
+	// otherwise by Sprintf, only when it's going to be written.
+	format  string
+	args    []interface{}
+	println bool
+	// Whether not count the entry in stats, for logs about logger itself.
+	uncounted bool
+	// The pc of caller, zero to find the first caller out of logger.
+	pc uintptr
+	// The caller such as file.go:42, and its function such as pkg.Func.
+	caller, function string
+	// The id of goroutine, zero if not shown.
+	goroutine uint64
+	// The delta since previous log of context, such as +1.234ms, empty if not shown.
+	delta string
+	// The topic of logger, see Topic.
+	topic string
+	// The extra prefix by SetPrefixFunc.
+	prefix string
+	// The message with full representation of Verbose args, empty if no Verbose args.
+	verboseMessage string
+	// The tags of context, see WithTags.
+	tags []string
+	// The sequence number, zero if not shown.
+	seq uint64
+}
+
+// The structured field of log entry.
+type field struct {
+	key   string
+	value interface{}
+}
+
+// A group of fields, which is a nested object in JSON,
+// while flatten with dotted keys in text, such as request.method=GET.
+type group []field
+
+// Set the constant fields of process, which are appended to fields of every log, for
+// example, to tag all logs of a sidecar:
+//		logger.WithConstantFields(map[string]string{"service": "foo", "env": "prod"})
+// Then the text log is "message env=prod service=foo", while the JSON log has the fields.
+// The fields are sorted by key, and the field of log overrides the constant one with
+// the same key. Set to nil to remove them.
+// @remark The fields are built once here, so there is no overhead for each log.
+func WithConstantFields(fields map[string]string) {
+	var constants []field
+	for k, v := range fields {
+		constants = append(constants, field{k, v})
+	}
+	sort.Slice(constants, func(i, j int) bool {
+		return constants[i].key < constants[j].key
+	})
+
+	updateOptions(func(o *options) {
+		o.constantFields = constants
+	})
+}
+
+// Append the fields, such as the constant fields, to the fields of entry, except the overridden ones.
+func (v *entry) appendMissingFields(fields []field) {
+	if len(v.fields) == 0 {
+		v.fields = fields
+		return
+	}
+
+	merged := append([]field(nil), v.fields...)
+	for _, c := range fields {
+		overridden := false
+		for _, f := range v.fields {
+			if f.key == c.key {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, c)
+		}
+	}
+	v.fields = merged
+}
+
+// Log the message with fields by l, for custom Logger, the fields are appended to message.
+func printFields(l Logger, ctx Context, message string, fields ...field) {
+	printEntry(l, &entry{ctx: ctx, message: message, fields: fields})
+}
+
+// Log the entry by l, for custom Logger, the fields are appended to message.
+func printEntry(l Logger, e *entry) {
+	if v, ok := l.(*loggerPlus); ok {
+		v.output(e)
+		return
+	}
+	l.Println(e.ctx, e.textMessage())
+}
+
+// Format the message by args, only once.
+func (v *entry) formatMessage(o *options) {
+	if v.args == nil && v.format == "" {
+		return
+	}
+
+	if o.textFormatter != nil {
+		v.args = formatArgs(o.textFormatter, v.args)
+	}
+	v.args = resolveLazyArgs(v.args)
+
+	v.message = strings.TrimRight(v.sprint(v.args), "\r\n")
+	if args, ok := verboseArgs(v.args); ok {
+		v.verboseMessage = v.sprint(args)
+	}
+	v.format, v.args = "", nil
+}
+
+func (v *entry) sprint(args []interface{}) string {
+	if v.println {
+		return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+	}
+	return fmt.Sprintf(v.format, args...)
+}
+
+// The message with fields in text, such as "message key=value".
+func (v *entry) textMessage() string {
+	var b bytes.Buffer
+	b.WriteString(v.message)
+	encodeTextTags(&b, v.tags)
+	encodeTextFields(&b, "", v.fields)
+	return b.String()
+}
+
+// The pool of buffers to encode entries, each entry uses one buffer from encode to write.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// The max bytes of buffer to put back to pool, the larger one is dropped to free memory.
+const maxPooledBuffer = 64 * 1024
+
+func getBuffer() *bytes.Buffer {
+	b := bufferPool.Get().(*bytes.Buffer)
+	if loadOptions().strictConcurrency {
+		strictBuffer(b, true)
+	}
+	b.Reset()
+	return b
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if loadOptions().strictConcurrency {
+		strictBuffer(b, false)
+	}
+	if b.Cap() <= maxPooledBuffer {
+		bufferPool.Put(b)
+	}
+}
+
+// Encode the entry as a line in format, the color is only used for text log.
+// @remark The line is a copy, which is safe to keep, see encodeTo to reuse a buffer.
+func (v *loggerPlus) encode(o *options, e *entry, format Format, color string) []byte {
+	b := getBuffer()
+	defer putBuffer(b)
+
+	v.encodeTo(b, o, e, format, color, nil)
+	return append([]byte(nil), b.Bytes()...)
+}
+
+// Encode the entry as a line in format and append to b, which is going to be written to w,
+// or nil if not written, see SetFoldPrefix.
+func (v *loggerPlus) encodeTo(b *bytes.Buffer, o *options, e *entry, format Format, color string, w io.Writer) {
+	if format == FormatProtobuf {
+		b.Write(v.encodeProto(e))
+		return
+	}
+
+	start := b.Len()
+	if format == FormatJSON {
+		v.encodeJSON(b, o, e)
+	} else {
+		v.encodeText(b, o, e, color, w)
+	}
+
+	// Exactly one line ending, and the embedded lines of text use the same ending.
+	line := bytes.TrimRight(b.Bytes()[start:], "\r\n")
+	if o.lineEnding == LineEndingCRLF {
+		line = bytes.ReplaceAll(bytes.ReplaceAll(line, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n"))
+		b.Truncate(start)
+		b.Write(line)
+		b.WriteString("\r\n")
+		return
+	}
+	b.Truncate(start + len(line))
+	b.WriteByte('\n')
+}
+
+// The line ending of log.
+type LineEnding int
+
+const (
+	// The LF, the default line ending.
+	LineEndingLF LineEnding = iota
+	// The CRLF, for some log consumers on Windows.
+	LineEndingCRLF
+)
+
+// Set the line ending of text and JSON log, LineEndingLF or LineEndingCRLF.
+// Each log has exactly one line ending, so the trailing newlines of message are trimmed,
+// and for CRLF, the embedded newlines of text log are also converted to CRLF.
+// @remark The protobuf log is framed by length, so it has no line ending.
+func SetLineEnding(ending LineEnding) {
+	updateOptions(WithLineEnding(ending))
+}
+
+// Text log, for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] message key=value
+// With caller and function:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] pkg.Func (file.go:42): message key=value
+func (v *loggerPlus) encodeText(b *bytes.Buffer, o *options, e *entry, color string, w io.Writer) {
+	b.WriteString(color)
+	b.WriteString(v.logger.Prefix())
+	if color != "" && o.colorScope == ScopeLabel {
+		b.WriteString(colorBlack)
+		color = ""
+	}
+	if o.columnAlign {
+		b.WriteString(strings.Repeat(" ", len(logErrorLabel)-len(v.logger.Prefix())))
+	}
+
+	flags := v.logger.Flags()
+	t := v.timeOf(o, e)
+	if o.timeMode == ModeUptime {
+		b.WriteString(uptimeOf(e))
+		b.WriteByte(' ')
+	} else if o.timeFormat != "" {
+		b.WriteString(t.Format(o.timeFormat))
+		b.WriteByte(' ')
+	} else {
+		if flags&log.Ldate != 0 {
+			b.WriteString(t.Format("2006/01/02 "))
+		}
+		if flags&log.Lmicroseconds != 0 {
+			b.WriteString(t.Format("15:04:05.000000 "))
+		} else if flags&log.Ltime != 0 {
+			b.WriteString(t.Format("15:04:05 "))
+		}
+	}
+
+	prefix := v.prefix(o, e.ctx)
+	if o.columnAlign && len(prefix) < columnPrefixWidth {
+		prefix += strings.Repeat(" ", columnPrefixWidth-len(prefix))
+	}
+	prefix = o.globalPrefix + prefix
+	if e.seq != 0 {
+		prefix += "[#" + strconv.FormatUint(e.seq, 10) + "]"
+	}
+	if e.goroutine != 0 {
+		prefix += "[g" + strconv.FormatUint(e.goroutine, 10) + "]"
+	}
+	if e.topic != "" {
+		prefix += "[" + e.topic + "]"
+	}
+	if prefix += e.prefix; prefix != "" {
+		if o.foldPrefix && w != nil {
+			prefix = foldPrefix(w, prefix)
+		}
+		b.WriteString(prefix)
+		b.WriteString(o.prefixSeparator)
+	}
+	if e.delta != "" {
+		b.WriteString(e.delta)
+		b.WriteByte(' ')
+	}
+	if e.function != "" {
+		b.WriteString(e.function)
+		b.WriteString(" (")
+		b.WriteString(e.caller)
+		b.WriteString("): ")
+	} else if e.caller != "" {
+		b.WriteString(e.caller)
+		b.WriteString(": ")
+	}
+	b.WriteString(e.textMessageOf(o))
+	encodeTextTags(b, e.tags)
+	encodeTextFields(b, "", e.fields)
+
+	if color != "" {
+		b.WriteString(colorBlack)
+	}
+}
+
+// The lines of field, which is an indented line for each in text, while an array in JSON.
+type lines []string
+
+func encodeTextFields(b *bytes.Buffer, parent string, fields []field) {
+	for _, f := range fields {
+		if g, ok := f.value.(group); ok {
+			encodeTextFields(b, parent+f.key+".", g)
+			continue
+		}
+		if p, ok := f.value.(jsonPayload); ok {
+			f.value = p.lines()
+		}
+		if l, ok := f.value.(lines); ok {
+			b.WriteByte(' ')
+			b.WriteString(parent)
+			b.WriteString(f.key)
+			b.WriteByte(':')
+			for _, line := range l {
+				b.WriteString("\n    ")
+				b.WriteString(line)
+			}
+			continue
+		}
+
+		b.WriteByte(' ')
+		b.WriteString(parent)
+		b.WriteString(f.key)
+		b.WriteByte('=')
+
+		s := textValue(f.value)
+		if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+			s = strconv.Quote(s)
+		}
+		b.WriteString(s)
+	}
+}
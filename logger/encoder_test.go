@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func benchmarkEntry() *entry {
+	return &entry{
+		ctx:     testCidContext(100),
+		message: "The log text.",
+		fields:  []field{{"key", "value"}, {"request", group{{"method", "GET"}}}},
+	}
+}
+
+// The naive encoding, which allocates a buffer for each entry.
+func BenchmarkEncode(b *testing.B) {
+	v, o, e := newLoggerPlus(ioutil.Discard, LevelTrace), loadOptions(), benchmarkEntry()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		v.encodeTo(&buf, o, e, FormatText, "", nil)
+		ioutil.Discard.Write(buf.Bytes())
+	}
+}
+
+// The encoding by pooled buffer, which is used by the write path.
+func BenchmarkEncodePooled(b *testing.B) {
+	v, o, e := newLoggerPlus(ioutil.Discard, LevelTrace), loadOptions(), benchmarkEntry()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := getBuffer()
+		v.encodeTo(buf, o, e, FormatText, "", nil)
+		ioutil.Discard.Write(buf.Bytes())
+		putBuffer(buf)
+	}
+}
+
+func BenchmarkWriteEntry(b *testing.B) {
+	v := newLoggerPlus(ioutil.Discard, LevelTrace)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.output(benchmarkEntry())
+	}
+}
+
+// The common case of one string arg, see BenchmarkTs.
+func BenchmarkT(b *testing.B) {
+	Switch(ioutil.Discard)
+	defer Switch(os.Stdout)
+
+	ctx := testCidContext(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		T(ctx, "The log text.")
+	}
+}
+
+// The fast path of single string, without the variadic args and formatting.
+func BenchmarkTs(b *testing.B) {
+	Switch(ioutil.Discard)
+	defer Switch(os.Stdout)
+
+	ctx := testCidContext(100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Ts(ctx, "The log text.")
+	}
+}
+
+func TestEncodeLineEnding(t *testing.T) {
+	v, e := newLoggerPlus(ioutil.Discard, LevelTrace), &entry{message: "a\nb\n"}
+
+	SetLineEnding(LineEndingCRLF)
+	defer SetLineEnding(LineEndingLF)
+
+	var b bytes.Buffer
+	b.WriteByte('[')
+	v.encodeTo(&b, loadOptions(), e, FormatJSON, "", nil)
+	if !bytes.HasPrefix(b.Bytes(), []byte("[{")) || !bytes.HasSuffix(b.Bytes(), []byte("\"a\\nb\\n\"}\r\n")) {
+		t.Errorf("unexpected %q", b.String())
+	}
+}
+
+func TestFormatMessageLazyOnce(t *testing.T) {
+	var calls int
+	e := &entry{println: true, args: []interface{}{"state", Lazy(func() string {
+		calls++
+		return "dump"
+	}), Verbose(1)}}
+
+	e.formatMessage(loadOptions())
+	if calls != 1 || e.message != "state dump 1" || e.verboseMessage != "state dump 1" {
+		t.Errorf("calls %v, message %q, verbose %q", calls, e.message, e.verboseMessage)
+	}
+}
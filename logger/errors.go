@@ -0,0 +1,40 @@
+package logger
+
+import "strconv"
+
+// Log the errors at Error level, each on its own indented line, for example, the
+// failures of batch operation:
+//		logger.Emany(ctx, errs...)
+// Then the log is:
+//		[error] 2006/01/02 15:04:05.000000 [pid][cid] 2 errors count=2 errors:
+//		    open a.txt: no such file
+//		    open b.txt: permission denied
+// The joined error such as errors.Join is unwrapped as multiple errors, and the nil
+// errors are skipped, nothing is logged if no error. In JSON, the errors field is an array.
+func Emany(ctx Context, errs ...error) {
+	var messages lines
+	for _, err := range errs {
+		messages = appendErrors(messages, err)
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	printFields(Error, ctx, strconv.Itoa(len(messages))+" errors",
+		field{"count", len(messages)}, field{"errors", messages},
+	)
+}
+
+// Append the messages of err, unwrap if it's joined by errors.Join.
+func appendErrors(messages lines, err error) lines {
+	if err == nil {
+		return messages
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			messages = appendErrors(messages, err)
+		}
+		return messages
+	}
+	return append(messages, err.Error())
+}
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Set whether escape the control characters in message, default to false, to harden the logs
+// of untrusted input, for example, a fake newline to inject a log:
+//		logger.SetEscapeControl(true)
+//		logger.T(ctx, "user is", "bob\n[error] 2006/01/02 15:04:05.000000 [1] fake")
+// Then the text log is one line:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] user is bob\n[error] 2006/01/02 15:04:05.000000 [1] fake
+// For text log, the newlines, tabs and other non-printable characters of message are escaped
+// as \n, \t, \x1b or \u202e. For JSON log, the JSON escaping is used, while the terminal escape
+// sequences and the bidirectional overrides are stripped, which are dangerous when printed.
+// @remark The values of fields are already quoted if have control characters, and the
+// 	indented lines of fields, such as Emany, are written by logger, so they're not escaped.
+func SetEscapeControl(escape bool) {
+	updateOptions(WithEscapeControl(escape))
+}
+
+// The message of entry for text log.
+func (v *entry) textMessageOf(o *options) string {
+	s := v.message
+	if o.verbose && v.verboseMessage != "" {
+		s = v.verboseMessage
+	}
+	if o.escapeControl {
+		s = escapeControl(s)
+	}
+	return s
+}
+
+// The message of entry for JSON log.
+func (v *entry) jsonMessageOf(o *options) string {
+	s := v.message
+	if v.verboseMessage != "" {
+		s = v.verboseMessage
+	}
+	if o.escapeControl {
+		s = stripControl(s)
+	}
+	return s
+}
+
+// Whether s has no control or non-ASCII characters, which is safe as is.
+func plainASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// Escape the non-printable characters of s, such as newline to \n.
+func escapeControl(s string) string {
+	if plainASCII(s) {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == utf8.RuneError || unicode.IsPrint(r):
+			b.WriteRune(r)
+		case r < 0x100:
+			b.WriteString(`\x`)
+			b.WriteString(strconv.FormatInt(int64(r)|0x100, 16)[1:])
+		default:
+			b.WriteString(`\u`)
+			b.WriteString(strconv.FormatInt(int64(r)|0x10000, 16)[1:])
+		}
+	}
+	return b.String()
+}
+
+// Strip the terminal escape sequences and bidirectional overrides of s.
+func stripControl(s string) string {
+	if plainASCII(s) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\x1b':
+			i += escapeSequenceLen(s[i:])
+			continue
+		case r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069':
+			// Strip the bidirectional overrides.
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// The length of escape sequence at start of s, such as \x1b[31m, at least 1 for ESC.
+func escapeSequenceLen(s string) int {
+	if len(s) < 2 || s[1] != '[' {
+		return 1
+	}
+	for i := 2; i < len(s); i++ {
+		if c := s[i]; c >= 0x40 && c <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,23 @@
+package logger
+
+import "testing"
+
+func TestEscapeControl(t *testing.T) {
+	cases := []struct {
+		s, escaped, stripped string
+	}{
+		{"plain text", "plain text", "plain text"},
+		{"a\nb\r\tc", `a\nb\r\tc`, "a\nb\r\tc"},
+		{"red\x1b[31m!\x1b[0m", `red\x1b[31m!\x1b[0m`, "red!"},
+		{"evil\u202etxt.exe é", `evil\u202etxt.exe é`, "eviltxt.exe é"},
+		{"bell\a", `bell\x07`, "bell\a"},
+	}
+	for _, c := range cases {
+		if s := escapeControl(c.s); s != c.escaped {
+			t.Errorf("escape %q, expect %v, actual %v", c.s, c.escaped, s)
+		}
+		if s := stripControl(c.s); s != c.stripped {
+			t.Errorf("strip %q, expect %q, actual %q", c.s, c.stripped, s)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+//go:build windows
+// +build windows
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// The event id of logs, which is in range 1 to 1000 of EventCreate.exe message file.
+const eventLogID = 1
+
+// The registry key of event sources of Application log.
+const eventLogSourceKey = `SYSTEM\CurrentControlSet\Services\EventLog\Application\`
+
+// Register the source of Windows Event Log, which requires administrator, so it's
+// usually done by the installer of service, for example:
+//		logger.RegisterEventLogSource("app")
+// It's ignored if the source is already registered.
+func RegisterEventLogSource(source string) error {
+	if eventLogSourceExists(source) {
+		return nil
+	}
+	return eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+}
+
+func eventLogSourceExists(source string) bool {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, eventLogSourceKey+source, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	k.Close()
+	return true
+}
+
+// Create the writer to Windows Event Log of source, which is the native log of
+// Windows service, for example:
+//		w, err := logger.NewEventLogWriter("app")
+// Each log is an event, whose type is by the level of log, which is parsed from the
+// label of text log or the level field of JSON log:
+//		Info and Trace to Information, Warn to Warning, Error to Error
+// The source must be registered, see RegisterEventLogSource.
+func NewEventLogWriter(source string) (io.WriteCloser, error) {
+	if !eventLogSourceExists(source) {
+		return nil, fmt.Errorf("event log source %q is not registered, "+
+			"register it by logger.RegisterEventLogSource as administrator", source)
+	}
+
+	el, err := eventlog.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	return &eventLogWriter{el: el}, nil
+}
+
+// Create the event log writer and switch to it, see NewEventLogWriter.
+// @remark Close or next Switch closes the writer.
+func SwitchEventLog(source string) error {
+	w, err := NewEventLogWriter(source)
+	if err != nil {
+		return err
+	}
+	switchOwned(w)
+	return nil
+}
+
+// The writer to Windows Event Log, one event for each line.
+type eventLogWriter struct {
+	lock sync.Mutex
+	el   *eventlog.Log
+	// The partial line, wait for the line ending.
+	pending []byte
+}
+
+func (v *eventLogWriter) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.el == nil {
+		return 0, os.ErrClosed
+	}
+
+	var err error
+	v.pending = splitLines(v.pending, p, func(line []byte) {
+		if err == nil {
+			err = v.writeLine(line)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (v *eventLogWriter) writeLine(line []byte) error {
+	level := LevelTrace
+	if e := parseEntry(line); e.HasLevel {
+		level = e.Level
+	}
+
+	switch level {
+	case LevelError:
+		return v.el.Error(eventLogID, string(line))
+	case LevelWarn:
+		return v.el.Warning(eventLogID, string(line))
+	default:
+		return v.el.Info(eventLogID, string(line))
+	}
+}
+
+func (v *eventLogWriter) Close() error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.el == nil {
+		return nil
+	}
+	err := v.el.Close()
+	v.el = nil
+	return err
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sync"
+)
+
+// The max continuous broken pipe errors, then fallback.
+const maxBrokenPipes = 3
+
+// The state of fallback, protected by outputLock.
+var fallback struct {
+	// The writer to use when the primary one is broken, default to discard.
+	w io.Writer
+	// The broken primary writer, nil if not broken.
+	broken io.Writer
+	// The continuous broken pipe errors.
+	errors int
+}
+
+var notifyPipeOnce sync.Once
+
+// Set the writer used when the underlayer io is broken, default to discard.
+// When the parent process closes our stdout pipe, every write fails with EPIPE,
+// then after 3 continuous EPIPE, the logger reports it once by handler of SetErrorHandler
+// or stderr, and falls back to w until Switch to another io, for example, fallback to a file:
+//		logger.SetFallbackWriter(f)
+// @remark Go kills the process by SIGPIPE when writing to closed stdout or stderr, so it
+// 	receives SIGPIPE to get EPIPE instead, which applies to the whole process, and the
+// 	fallback of stdout only works after it, even w is nil to discard. The other broken
+// 	pipes always return EPIPE, so they fall back to discard without it.
+func SetFallbackWriter(w io.Writer) {
+	notifyPipeOnce.Do(notifyBrokenPipe)
+
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	fallback.w = w
+}
+
+// The writer to use, the fallback writer if w is broken.
+// @remark The outputLock must be held.
+func fallbackWriter(w io.Writer) io.Writer {
+	if fallback.broken == nil || !sameWriter(w, fallback.broken) {
+		return w
+	}
+	if fallback.w != nil {
+		return fallback.w
+	}
+	return ioutil.Discard
+}
+
+// Check the result of writing to w, and fallback if it's broken, return err which tells
+// the fallback if any, to report by handleError.
+// @remark The outputLock must be held.
+func checkBrokenPipe(w io.Writer, err error) error {
+	if err == nil || !isBrokenPipe(err) {
+		fallback.errors = 0
+		return err
+	}
+
+	if fallback.errors++; fallback.errors < maxBrokenPipes || sameWriter(w, fallback.broken) {
+		return err
+	}
+
+	fallback.broken, fallback.errors = w, 0
+	return fmt.Errorf("%w, fallback for broken pipe", err)
+}
+
+// Whether a and b are the same writer, false if not comparable.
+func sameWriter(a, b io.Writer) bool {
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,30 @@
+package logger
+
+import "strings"
+
+// Trace level log of the single preformatted string, the fast path of T for the most
+// common case, which avoids the variadic args and the formatting, for example:
+//		logger.Ts(ctx, "serve request")
+// The log is same to T with one string arg, see BenchmarkTs.
+func Ts(ctx Context, s string) {
+	printString(Trace, ctx, s)
+}
+
+// Warn level log of the single preformatted string, see Ts.
+func Ws(ctx Context, s string) {
+	printString(Warn, ctx, s)
+}
+
+// Error level log of the single preformatted string, see Ts.
+func Es(ctx Context, s string) {
+	printString(Error, ctx, s)
+}
+
+// Log s as the message by l, for custom Logger, it's Println.
+func printString(l Logger, ctx Context, s string) {
+	if v, ok := l.(*loggerPlus); ok {
+		v.output(&entry{ctx: ctx, message: strings.TrimRight(s, "\r\n")})
+		return
+	}
+	l.Println(ctx, s)
+}
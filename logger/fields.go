@@ -0,0 +1,67 @@
+package logger
+
+// The key of fields in context.
+type fieldsKey struct{}
+
+// Derive ctx with the field, which is appended to all logs of the derived context, for example:
+//		ctx = logger.WithField(ctx, "user", userID)
+//		logger.T(ctx, "login") // [trace] 2006/01/02 15:04:05.000000 [pid][cid] login user=42
+// The field of log overrides the field of context with the same key, while the field of
+// context overrides the constant field, see WithConstantFields.
+// @remark The fields are collected once for each log, and shared by the underlayer io and
+// 	all sinks, so it's text for console and JSON for file, see AddSink.
+// @remark The cid of ctx is kept, see PushPrefix.
+func WithField(ctx Context, key string, value interface{}) Context {
+	parent := contextFieldsOf(ctx)
+	fields := make([]field, 0, len(parent)+1)
+	for _, f := range parent {
+		if f.key != key {
+			fields = append(fields, f)
+		}
+	}
+	return withValue(ctx, fieldsKey{}, append(fields, field{key, value}))
+}
+
+// The value of field which is computed when the log is going to be written.
+type lazyField func() interface{}
+
+// Derive ctx with the field whose value is computed by fn only when the log is going to be
+// written, for the expensive value, for example:
+//		ctx = logger.WithLazyField(ctx, "queue", func() interface{} {
+//			return queue.Stats()
+//		})
+// The fn is called after the level, sampling and limits are checked, so it's never called
+// for the dropped logs, and it's called at most once for each log, shared by all sinks.
+// @remark The fn is called in the goroutine which writes the log, even with NewAsyncWriter,
+// 	which queues the encoded logs, so it must be safe to call from any goroutine that logs.
+// @remark It's called if the log is buffered by SetFlushOnError, even it's discarded later.
+func WithLazyField(ctx Context, key string, fn func() interface{}) Context {
+	return WithField(ctx, key, lazyField(fn))
+}
+
+// Compute the values of lazy fields, the fields are copied if any.
+func (v *entry) resolveLazyFields() {
+	for i, f := range v.fields {
+		if _, ok := f.value.(lazyField); !ok {
+			continue
+		}
+
+		fields := append([]field(nil), v.fields...)
+		for j := i; j < len(fields); j++ {
+			if fn, ok := fields[j].value.(lazyField); ok {
+				fields[j].value = fn()
+			}
+		}
+		v.fields = fields
+		return
+	}
+}
+
+// The fields of ctx by WithField, nil if none.
+func contextFieldsOf(ctx Context) []field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := valueOf(ctx, fieldsKey{}).([]field)
+	return fields
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWithFieldSinks(t *testing.T) {
+	var console bytes.Buffer
+	Switch(&console)
+	defer Switch(os.Stdout)
+
+	file := NewCaptureWriter()
+	AddSink(file, FormatJSON, LevelTrace)
+	defer removeSinks()
+
+	ctx := WithField(WithField(testCidContext(100), "user", 42), "user", 7)
+	Tkv(ctx, "login", "method", "password")
+
+	if s := console.String(); !strings.HasSuffix(s, fmt.Sprintf("[%v][100] login method=password user=7\n", os.Getpid())) {
+		t.Errorf("unexpected console log %q", s)
+	}
+
+	entries := file.Get()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, actual %v", len(entries))
+	}
+	e := entries[0]
+	if e.Message != "login" || !e.HasCid || e.Cid != 100 || fmt.Sprint(e.Fields["method"]) != "password" ||
+		fmt.Sprint(e.Fields["user"]) != "7" || len(e.Fields) != 2 {
+		t.Errorf("unexpected file log %+v", e)
+	}
+}
+
+func TestWithTags(t *testing.T) {
+	var console bytes.Buffer
+	Switch(&console)
+	defer Switch(os.Stdout)
+
+	file := NewCaptureWriter()
+	AddSink(file, FormatJSON, LevelTrace)
+	defer removeSinks()
+
+	SetRingSize(10)
+	defer SetRingSize(0)
+
+	ctx := WithTags(WithTags(testCidContext(100), "db"), "slow", "db")
+	Tkv(ctx, "query", "table", "users")
+	T(nil, "untagged")
+
+	if s := console.String(); !strings.Contains(s, fmt.Sprintf("[%v][100] query #db #slow table=users\n", os.Getpid())) {
+		t.Errorf("unexpected console log %q", s)
+	}
+
+	entries := file.Get()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, actual %v", len(entries))
+	}
+	if e := entries[0]; !e.HasTag("db") || !e.HasTag("slow") || len(e.Tags) != 2 || len(e.Fields) != 1 {
+		t.Errorf("unexpected file log %+v", e)
+	}
+	if e := entries[1]; e.Tags != nil {
+		t.Errorf("unexpected tags %v", e.Tags)
+	}
+
+	var b bytes.Buffer
+	if err := DumpRingWithTag(&b, "slow"); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.String(); strings.Count(s, "\n") != 1 || !strings.Contains(s, "query #db #slow") {
+		t.Errorf("unexpected ring %q", s)
+	}
+}
+
+func TestShowSequence(t *testing.T) {
+	var console bytes.Buffer
+	Switch(&console)
+	defer Switch(os.Stdout)
+
+	file := NewCaptureWriter()
+	AddSink(file, FormatJSON, LevelTrace)
+	defer removeSinks()
+
+	SetShowSequence(true)
+	defer SetShowSequence(false)
+
+	T(nil, "first")
+	T(nil, "second")
+
+	entries := file.Get()
+	if len(entries) != 2 || entries[0].Seq == 0 || entries[1].Seq != entries[0].Seq+1 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	expect := fmt.Sprintf("[%v][#%v] second\n", os.Getpid(), entries[1].Seq)
+	if s := console.String(); !strings.HasSuffix(s, expect) {
+		t.Errorf("expect suffix %q, actual %q", expect, s)
+	}
+}
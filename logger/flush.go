@@ -0,0 +1,115 @@
+package logger
+
+import "container/list"
+
+// The max buffered logs of each context, the oldest is dropped when exceed.
+const maxBufferedLines = 1000
+
+// The max buffered contexts, the least recently logged is dropped when exceed.
+const maxBufferedContexts = 1000
+
+// The buffered entry and its logger.
+type bufferedLine struct {
+	logger *loggerPlus
+	e      *entry
+}
+
+// The ring of buffered logs of a context.
+type bufferedContext struct {
+	cid   int
+	lines []bufferedLine
+	// The index of the oldest line when the ring is full.
+	head int
+	// The element in bufferedLRU.
+	elem *list.Element
+}
+
+// The buffered logs, key by cid, and the contexts from least to most recently logged,
+// protected by outputLock.
+var (
+	bufferedLines map[int]*bufferedContext
+	bufferedLRU   list.List
+)
+
+// Set whether buffer the Info and Trace logs of each context in memory, and only
+// write them when an Error is logged for the context, so there are detailed logs
+// exactly when something goes wrong, for example:
+//		logger.SetFlushOnError(true)
+//		defer logger.DiscardBuffered(ctx) // When connection is done.
+//		logger.T(ctx, "step 1") // Buffered.
+//		logger.E(ctx, "failed") // Write step 1, then the error.
+// The logs are buffered by cid, at most 1000 logs for each context, and the oldest
+// is dropped when exceed. At most 1000 contexts are buffered, and the logs of the least
+// recently logged context are discarded when exceed, so the memory is bounded even the
+// contexts never log an Error. The Warn logs and logs without cid are written directly.
+// @remark Call DiscardBuffered when the context is done, to free the memory earlier.
+// @remark The level is still checked, use SetLevel(LevelInfo) to buffer the Info logs.
+func SetFlushOnError(enabled bool) {
+	updateOptions(WithFlushOnError(enabled))
+
+	if !enabled {
+		outputLock.Lock()
+		bufferedLines = nil
+		bufferedLRU.Init()
+		outputLock.Unlock()
+	}
+}
+
+// Discard the buffered logs of ctx, generally when the connection is done.
+func DiscardBuffered(ctx Context) {
+	if cid, ok := cidOf(ctx); ok {
+		outputLock.Lock()
+		removeBuffered(cid)
+		outputLock.Unlock()
+	}
+}
+
+// @remark The outputLock must be held.
+func bufferLine(cid int, v *loggerPlus, e *entry) {
+	if bufferedLines == nil {
+		bufferedLines = make(map[int]*bufferedContext)
+	}
+
+	c := bufferedLines[cid]
+	if c == nil {
+		if bufferedLRU.Len() >= maxBufferedContexts {
+			removeBuffered(bufferedLRU.Front().Value.(*bufferedContext).cid)
+		}
+		c = &bufferedContext{cid: cid}
+		c.elem = bufferedLRU.PushBack(c)
+		bufferedLines[cid] = c
+	} else {
+		bufferedLRU.MoveToBack(c.elem)
+	}
+
+	line := bufferedLine{logger: v, e: e}
+	if len(c.lines) < maxBufferedLines {
+		c.lines = append(c.lines, line)
+		return
+	}
+	c.lines[c.head] = line
+	c.head = (c.head + 1) % len(c.lines)
+}
+
+// @remark The outputLock must be held.
+func flushBuffered(o *options, cid int) {
+	c := bufferedLines[cid]
+	if c == nil {
+		return
+	}
+	removeBuffered(cid)
+
+	for _, lines := range [][]bufferedLine{c.lines[c.head:], c.lines[:c.head]} {
+		for _, line := range lines {
+			line.logger.writeEntry(o, line.e)
+		}
+	}
+}
+
+// @remark The outputLock must be held.
+func removeBuffered(cid int) {
+	if c := bufferedLines[cid]; c != nil {
+		bufferedLRU.Remove(c.elem)
+		delete(bufferedLines, cid)
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFlushOnErrorRing(t *testing.T) {
+	var buf bytes.Buffer
+	Switch(&buf)
+	defer Switch(os.Stdout)
+	SetFlushOnError(true)
+	defer SetFlushOnError(false)
+
+	ctx := testCidContext(100)
+	for i := 0; i <= maxBufferedLines; i++ {
+		T(ctx, fmt.Sprintf("step-%v.", i))
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected written %q", buf.String())
+	}
+
+	E(ctx, "failed")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != maxBufferedLines+1 || !strings.HasSuffix(lines[0], " step-1.") ||
+		!strings.HasSuffix(lines[maxBufferedLines-1], fmt.Sprintf(" step-%v.", maxBufferedLines)) ||
+		!strings.HasSuffix(lines[maxBufferedLines], " failed") {
+		t.Errorf("unexpected %v lines, first %q", len(lines), lines[0])
+	}
+}
+
+func TestFlushOnErrorEvict(t *testing.T) {
+	var buf bytes.Buffer
+	Switch(&buf)
+	defer Switch(os.Stdout)
+	SetFlushOnError(true)
+	defer SetFlushOnError(false)
+
+	for cid := 0; cid <= maxBufferedContexts; cid++ {
+		T(testCidContext(cid), fmt.Sprintf("step of %v.", cid))
+	}
+	if len(bufferedLines) != maxBufferedContexts || bufferedLRU.Len() != maxBufferedContexts {
+		t.Fatalf("unexpected %v buffered contexts", len(bufferedLines))
+	}
+
+	// The least recently logged context is evicted.
+	E(testCidContext(0), "failed")
+	E(testCidContext(1), "failed")
+	if s := buf.String(); strings.Contains(s, "step of 0.") || !strings.Contains(s, "step of 1.") {
+		t.Errorf("unexpected %q", s)
+	}
+}
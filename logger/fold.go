@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// The max writers to track the last prefix, all are reset when exceed.
+const maxFoldWriters = 64
+
+// The last prefix of each writer, protected by outputLock.
+var foldLasts []foldLast
+
+type foldLast struct {
+	w      io.Writer
+	prefix string
+}
+
+// Set whether fold the identical prefix of consecutive lines in text log, default to false,
+// to declutter the burst of a connection, for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] start
+//		[trace] 2006/01/02 15:04:05.000001                handshake
+//		[trace] 2006/01/02 15:04:05.000002                done
+// The prefix is replaced by spaces until it changes, which is tracked for each writer,
+// such as the underlayer io and sinks.
+// @remark It's only for text log, the JSON log always has full fields.
+func SetFoldPrefix(fold bool) {
+	updateOptions(WithFoldPrefix(fold))
+}
+
+// The prefix to write to w, spaces if same to the last one of w.
+// @remark The outputLock must be held.
+func foldPrefix(w io.Writer, prefix string) string {
+	for i := range foldLasts {
+		if sameWriter(foldLasts[i].w, w) {
+			if foldLasts[i].prefix == prefix {
+				return strings.Repeat(" ", utf8.RuneCountInString(prefix))
+			}
+			foldLasts[i].prefix = prefix
+			return prefix
+		}
+	}
+
+	if sameWriter(w, w) {
+		if len(foldLasts) >= maxFoldWriters {
+			foldLasts = nil
+		}
+		foldLasts = append(foldLasts, foldLast{w: w, prefix: prefix})
+	}
+	return prefix
+}
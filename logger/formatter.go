@@ -0,0 +1,46 @@
+package logger
+
+import "fmt"
+
+// Set the formatters of values, which replace the %v of args and fields when return true,
+// for example, to render proto.Message readable without the dependency in logger:
+//		logger.SetValueFormatter(func(v interface{}) (string, bool) {
+//			if m, ok := v.(proto.Message); ok {
+//				return prototext.MarshalOptions{}.Format(m), true
+//			}
+//			return "", false
+//		}, nil)
+// The text formatter is for args of message and fields in text log, while the JSON
+// formatter is for fields in JSON log, which returns the raw JSON value.
+// Set to nil to remove them. See package protolog for proto.Message.
+// @remark The formatters are called for each value, so they should be fast.
+func SetValueFormatter(text func(v interface{}) (string, bool), json func(v interface{}) ([]byte, bool)) {
+	updateOptions(WithValueFormatter(text, json))
+}
+
+// Replace the args by the text formatter, copy args if any is changed.
+func formatArgs(text func(v interface{}) (string, bool), args []interface{}) []interface{} {
+	var formatted []interface{}
+	for i, arg := range args {
+		if s, ok := text(arg); ok {
+			if formatted == nil {
+				formatted = append([]interface{}(nil), args...)
+			}
+			formatted[i] = s
+		}
+	}
+	if formatted == nil {
+		return args
+	}
+	return formatted
+}
+
+// The text of field value, by the text formatter or %v.
+func textValue(v interface{}) string {
+	if text := loadOptions().textFormatter; text != nil {
+		if s, ok := text(v); ok {
+			return s
+		}
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,63 @@
+// The ginlog package adapts logger to gin, which is separated from logger to keep
+// the gin dependency optional, for example:
+//		r := gin.New()
+//		r.Use(ginlog.Middleware())
+// Then each request has its own cid, and the logs of the request carry it:
+//		logger.T(ginlog.Context(c), "handle request")
+// The access log is written by logger.LogStatus after the handlers, for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] GET /api 1.5ms status=200
+// To keep the access log of gin instead, route its output to logger:
+//		r.Use(gin.LoggerWithConfig(gin.LoggerConfig{Output: ginlog.Writer(logger.Trace)}))
+package ginlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cheenwe/learn-go/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Create the middleware which binds a new cid to the request by logger.NewContext,
+// and logs the method, path and duration with status by logger.LogStatus,
+// so the level is decided by status, see logger.SetStatusLevel.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := logger.NewContext(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		start := time.Now()
+		c.Next()
+
+		logger.LogStatus(ctx, c.Writer.Status(), fmt.Sprintf("%v %v %v", c.Request.Method, c.Request.URL.Path, time.Since(start)))
+	}
+}
+
+// The context of logger for the request, which carries the cid of Middleware.
+func Context(c *gin.Context) logger.Context {
+	return c.Request.Context()
+}
+
+// Create the writer for gin.LoggerConfig.Output or gin.DefaultWriter, which logs each line
+// by l without cid, for example, the access log of gin at Trace level:
+//		gin.LoggerWithConfig(gin.LoggerConfig{Output: ginlog.Writer(logger.Trace)})
+// @remark The line across writes is logged as two, which never happens for gin,
+// 	for it writes one line at a time.
+func Writer(l logger.Logger) io.Writer {
+	return &lineWriter{l: l}
+}
+
+type lineWriter struct {
+	l logger.Logger
+}
+
+func (v *lineWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if line = bytes.TrimRight(line, "\r"); len(line) > 0 {
+			v.l.Printf(nil, "%s", line)
+		}
+	}
+	return len(p), nil
+}
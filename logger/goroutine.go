@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// Set whether show the id of current goroutine in prefix, such as [pid][cid][g42], default
+// to false, to correlate the interleaved logs from many goroutines. In JSON, it's the
+// goroutine field.
+// @remark It's expensive, for the id is parsed from runtime.Stack for each log,
+// 	so only enable it for debugging.
+func SetShowGoroutineID(show bool) {
+	updateOptions(WithShowGoroutineID(show))
+}
+
+// The id of current goroutine, parsed from the stack such as "goroutine 42 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if pos := bytes.IndexByte(b, ' '); pos > 0 {
+		b = b[:pos]
+	}
+
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,45 @@
+// The grpcmeta package tags logs by the metadata of gRPC incoming context,
+// which is separated from logger to keep the grpc dependency optional.
+//		logger.SetPrefixFunc(grpcmeta.PrefixFunc("x-request-id"))
+// Then in the gRPC handler, the logs of ctx carry the request id:
+//		logger.T(ctx, "handle request")
+// The log is "[x-request-id=abc] handle request". In JSON, it's the prefix field.
+package grpcmeta
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cheenwe/learn-go/logger"
+	"google.golang.org/grpc/metadata"
+)
+
+// Create the prefix function for logger.SetPrefixFunc, which writes the values
+// of keys in metadata of gRPC incoming context, such as [x-request-id=abc].
+// The missing keys are ignored, and the multiple values are joined by comma.
+// @remark The key is case-insensitive, as gRPC metadata.
+func PrefixFunc(keys ...string) func(ctx logger.Context) string {
+	return func(ctx logger.Context) string {
+		var b strings.Builder
+		for _, key := range keys {
+			if values := Values(ctx, key); len(values) > 0 {
+				b.WriteString("[" + strings.ToLower(key) + "=" + strings.Join(values, ",") + "]")
+			}
+		}
+		return b.String()
+	}
+}
+
+// The values of key in metadata of gRPC incoming context, nil if not found.
+func Values(ctx logger.Context, key string) []string {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(c)
+	if !ok {
+		return nil
+	}
+	return md.Get(key)
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The stats of logs since start, updated atomically.
+var stats struct {
+	// The written logs of each level.
+	counts [len(levelLabels)]uint64
+	// The logs dropped, for example, failed to write.
+	dropped uint64
+	// The logs kept in WAL to write later, see EnableWAL.
+	pending uint64
+	// The nanoseconds spent in logger, see SetMeasureOverhead.
+	overhead uint64
+}
+
+// The stats of logs, see Stats.
+type LogStats struct {
+	// The written logs of each level, index by Level.
+	Counts [len(levelLabels)]uint64
+	// The logs dropped, for example, failed to write or limited.
+	Dropped uint64
+	// The logs kept in WAL to write later, which are counted when written, see EnableWAL.
+	Pending uint64
+	// The total time spent in logger, only if SetMeasureOverhead.
+	Overhead time.Duration
+}
+
+// The stats of logs, for metrics, for example, the fraction of time spent in logging:
+//		logger.SetMeasureOverhead(true)
+//		......
+//		fmt.Println(logger.Stats().Overhead.Seconds() / uptime.Seconds())
+// @remark The counts and dropped are since start, even SetHeartbeat, which logs the
+// 	differences since last heartbeat.
+func Stats() LogStats {
+	var s LogStats
+	for level := range stats.counts {
+		s.Counts[level] = atomic.LoadUint64(&stats.counts[level])
+	}
+	s.Dropped = atomic.LoadUint64(&stats.dropped)
+	s.Pending = atomic.LoadUint64(&stats.pending)
+	s.Overhead = time.Duration(atomic.LoadUint64(&stats.overhead))
+	return s
+}
+
+// Set whether measure the time spent in logger, default to false, see Stats.
+// The time is from the log is enabled to it's written to all writers, by monotonic clock.
+// @remark It's a small cost to read the clock for each log, so it's optional.
+func SetMeasureOverhead(measure bool) {
+	updateOptions(WithMeasureOverhead(measure))
+}
+
+var heartbeatLock sync.Mutex
+var heartbeatStop, heartbeatDone chan struct{}
+
+// Log a Trace line every interval, which summarizes the logs since last heartbeat,
+// to confirm the process is alive and how noisy it is, for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid] heartbeat info=0 trace=120 warn=2 error=0 dropped=0 level=trace
+// Set interval to 0 to stop it, and it's also stopped by Close.
+// @remark The heartbeat line itself is not counted.
+func SetHeartbeat(interval time.Duration) {
+	heartbeatLock.Lock()
+	defer heartbeatLock.Unlock()
+
+	// Wait for the goroutine to quit, so no heartbeat after stopped.
+	if heartbeatStop != nil {
+		close(heartbeatStop)
+		<-heartbeatDone
+		heartbeatStop, heartbeatDone = nil, nil
+	}
+	if interval <= 0 {
+		return
+	}
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	heartbeatStop, heartbeatDone = stop, done
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := Stats()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				last = heartbeat(last)
+			}
+		}
+	}()
+}
+
+// Log the differences of stats since last, return the current stats.
+func heartbeat(last LogStats) LogStats {
+	s := Stats()
+	var fields []field
+	for level := range s.Counts {
+		fields = append(fields, field{Level(level).String(), s.Counts[level] - last.Counts[level]})
+	}
+	fields = append(fields,
+		field{"dropped", s.Dropped - last.Dropped},
+		field{"level", loadOptions().level.String()},
+	)
+
+	if v, ok := Trace.(*loggerPlus); ok {
+		v.output(&entry{message: "heartbeat", fields: fields, uncounted: true})
+	}
+	return s
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestHeartbeatKeepsStats(t *testing.T) {
+	var buf bytes.Buffer
+	Switch(&buf)
+	defer Switch(os.Stdout)
+
+	before := Stats()
+	T(nil, "one")
+	T(nil, "two")
+	last := heartbeat(before)
+	if !strings.Contains(buf.String(), " heartbeat info=0 trace=2 warn=0 error=0 dropped=0 ") {
+		t.Errorf("unexpected heartbeat %q", buf.String())
+	}
+
+	// The stats are still cumulative after heartbeat.
+	if s := Stats(); s.Counts[LevelTrace] != before.Counts[LevelTrace]+2 || s != last {
+		t.Errorf("unexpected stats %+v, before %+v", s, before)
+	}
+
+	buf.Reset()
+	T(nil, "three")
+	heartbeat(last)
+	if !strings.Contains(buf.String(), " heartbeat info=0 trace=1 ") {
+		t.Errorf("unexpected heartbeat %q", buf.String())
+	}
+}
@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Set the headers to log for HTTP request and response, default to
+// Content-Type, Content-Length and User-Agent.
+// @remark Never allow the secret headers such as Authorization and Cookie.
+func SetHTTPHeaders(headers ...string) {
+	updateOptions(WithHTTPHeaders(headers...))
+}
+
+// Set the max bytes of body to log for HTTP request and response,
+// default to 0 that never log the body.
+func SetHTTPBodyLimit(n int) {
+	updateOptions(WithHTTPBodyLimit(n))
+}
+
+// Log the HTTP request at Trace level, with method, path, remote address and the allowed headers,
+// for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] http request request.method=GET request.path=/api
+// @remark The body is restored after read, so it's safe to use r.Body after log it.
+// @remark Nothing is read if Trace is disabled for ctx.
+func LogRequest(ctx Context, r *http.Request) {
+	o := loadOptions()
+	if !traceEnabled(o, ctx) {
+		return
+	}
+
+	g := group{
+		{"method", r.Method},
+		{"path", r.URL.Path},
+		{"remote", r.RemoteAddr},
+	}
+	if h := httpHeaders(o, r.Header); h != nil {
+		g = append(g, field{"headers", h})
+	}
+	if o.httpBodyLimit > 0 && r.Body != nil {
+		var body []byte
+		body, r.Body = peekBody(r.Body, o.httpBodyLimit)
+		g = append(g, field{"body", string(body)})
+	}
+
+	printFields(Trace, ctx, "http request", field{"request", g})
+}
+
+// Log the HTTP response at Trace level, with status, duration and the allowed headers,
+// for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] http response response.status=200 response.duration_ms=1.5
+// @remark The body is restored after read, so it's safe to use resp.Body after log it.
+// @remark Nothing is read if Trace is disabled for ctx.
+func LogResponse(ctx Context, resp *http.Response, duration time.Duration) {
+	o := loadOptions()
+	if !traceEnabled(o, ctx) {
+		return
+	}
+
+	var g group
+	if r := resp.Request; r != nil {
+		g = append(g, field{"method", r.Method}, field{"path", r.URL.Path})
+	}
+	g = append(g,
+		field{"status", resp.StatusCode},
+		field{"duration_ms", float64(duration) / float64(time.Millisecond)},
+	)
+	if h := httpHeaders(o, resp.Header); h != nil {
+		g = append(g, field{"headers", h})
+	}
+	if o.httpBodyLimit > 0 && resp.Body != nil {
+		var body []byte
+		body, resp.Body = peekBody(resp.Body, o.httpBodyLimit)
+		g = append(g, field{"body", string(body)})
+	}
+
+	printFields(Trace, ctx, "http response", field{"response", g})
+}
+
+// Log the message with status, at the level by status, for example, in HTTP handler:
+//		logger.LogStatus(ctx, http.StatusNotFound, "no stream")
+// Then the log is:
+//		[warn] 2006/01/02 15:04:05.000000 [pid][cid] no stream status=404
+// The level is Warn for 4xx, Error for 5xx, and Trace for others, see SetStatusLevel.
+func LogStatus(ctx Context, status int, msg string) {
+	level := StatusLevel(status)
+	if fn := loadOptions().statusLevel; fn != nil {
+		level = fn(status)
+	}
+	printFields(levelLogger(level), ctx, msg, field{"status", status})
+}
+
+// Set the function to map HTTP status to level for LogStatus, for example,
+// to log 404 as Trace:
+//		logger.SetStatusLevel(func(status int) logger.Level {
+//			if status == http.StatusNotFound {
+//				return logger.LevelTrace
+//			}
+//			return logger.StatusLevel(status)
+//		})
+// Set to nil to use StatusLevel, which is the default.
+func SetStatusLevel(fn func(status int) Level) {
+	updateOptions(WithStatusLevel(fn))
+}
+
+// The default level of HTTP status, Warn for 4xx, Error for 5xx, and Trace for others.
+func StatusLevel(status int) Level {
+	switch {
+	case status >= 500:
+		return LevelError
+	case status >= 400:
+		return LevelWarn
+	default:
+		return LevelTrace
+	}
+}
+
+// Whether the Trace level is enabled for ctx, always true for custom Logger.
+func traceEnabled(o *options, ctx Context) bool {
+	l, ok := Trace.(*loggerPlus)
+	return !ok || l.enabled(o, ctx, "")
+}
+
+// Pick the allowed headers, nil if none.
+func httpHeaders(o *options, header http.Header) group {
+	var g group
+	for _, k := range o.httpHeaders {
+		if v := header.Get(k); v != "" {
+			g = append(g, field{k, v})
+		}
+	}
+	return g
+}
+
+// Read at most n bytes from body, and return a body which reads all data again.
+func peekBody(body io.ReadCloser, n int) ([]byte, io.ReadCloser) {
+	data, _ := ioutil.ReadAll(io.LimitReader(body, int64(n)))
+	return data, &peekedBody{Reader: io.MultiReader(bytes.NewReader(data), body), Closer: body}
+}
+
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogRequest(t *testing.T) {
+	Switch(ioutil.Discard)
+	defer Switch(os.Stdout)
+
+	file := NewCaptureWriter()
+	AddSink(file, FormatJSON, LevelTrace)
+	defer removeSinks()
+	defer SetHTTPBodyLimit(0)
+
+	cases := []struct {
+		name      string
+		limit     int
+		body      string
+		expect    map[string]string
+		notExpect []string
+	}{
+		{"headers", 0, "", map[string]string{"method": "POST", "path": "/api", "headers.User-Agent": "test"},
+			[]string{"headers.Authorization", "body"}},
+		{"no body", 0, "hello world", map[string]string{"method": "POST"}, []string{"body"}},
+		{"peek body", 5, "hello world", map[string]string{"body": "hello"}, nil},
+		{"short body", 100, "hello", map[string]string{"body": "hello"}, nil},
+	}
+	for _, c := range cases {
+		file.Reset()
+		SetHTTPBodyLimit(c.limit)
+
+		r := httptest.NewRequest("POST", "/api", strings.NewReader(c.body))
+		r.Header.Set("User-Agent", "test")
+		r.Header.Set("Authorization", "Bearer secret")
+		LogRequest(nil, r)
+
+		entries := file.Get()
+		if len(entries) != 1 || entries[0].Message != "http request" {
+			t.Fatalf("%v: unexpected entries %+v", c.name, entries)
+		}
+		request, _ := entries[0].Fields["request"].(map[string]interface{})
+		for k, v := range c.expect {
+			if actual, ok := httpField(request, k); !ok || fmt.Sprint(actual) != v {
+				t.Errorf("%v: expect %v=%v, actual %v", c.name, k, v, request)
+			}
+		}
+		for _, k := range c.notExpect {
+			if _, ok := httpField(request, k); ok {
+				t.Errorf("%v: unexpected %v in %v", c.name, k, request)
+			}
+		}
+		if strings.Contains(string(entries[0].Raw), "secret") {
+			t.Errorf("%v: secret header is logged %s", c.name, entries[0].Raw)
+		}
+
+		// The body is restored in full, even it exceeds the limit.
+		if b, _ := ioutil.ReadAll(r.Body); string(b) != c.body {
+			t.Errorf("%v: expect body %q, actual %q", c.name, c.body, b)
+		}
+	}
+}
+
+func TestLogResponse(t *testing.T) {
+	Switch(ioutil.Discard)
+	defer Switch(os.Stdout)
+
+	file := NewCaptureWriter()
+	AddSink(file, FormatJSON, LevelTrace)
+	defer removeSinks()
+	SetHTTPBodyLimit(2)
+	defer SetHTTPBodyLimit(0)
+
+	resp := &http.Response{
+		StatusCode: 404,
+		Header:     http.Header{"Content-Type": {"text/plain"}, "Set-Cookie": {"secret"}},
+		Body:       ioutil.NopCloser(strings.NewReader("not found")),
+		Request:    httptest.NewRequest("GET", "/api", nil),
+	}
+	LogResponse(nil, resp, 1500*time.Microsecond)
+
+	entries := file.Get()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, actual %v", len(entries))
+	}
+	response, _ := entries[0].Fields["response"].(map[string]interface{})
+	for k, v := range map[string]string{"method": "GET", "path": "/api", "status": "404", "duration_ms": "1.5",
+		"headers.Content-Type": "text/plain", "body": "no"} {
+		if actual, ok := httpField(response, k); !ok || fmt.Sprint(actual) != v {
+			t.Errorf("expect %v=%v, actual %v", k, v, response)
+		}
+	}
+	if _, ok := httpField(response, "headers.Set-Cookie"); ok {
+		t.Errorf("unexpected cookie in %v", response)
+	}
+	if b, _ := ioutil.ReadAll(resp.Body); string(b) != "not found" {
+		t.Errorf("unexpected body %q", b)
+	}
+}
+
+func TestLogRequestDisabled(t *testing.T) {
+	SetHTTPBodyLimit(4)
+	defer SetHTTPBodyLimit(0)
+	SetLevel(LevelWarn)
+	defer SetLevel(LevelTrace)
+
+	r := httptest.NewRequest("POST", "/api", strings.NewReader("body"))
+	body := r.Body
+	LogRequest(nil, r)
+	if r.Body != body {
+		t.Error("body is peeked for disabled level")
+	}
+	if b, _ := ioutil.ReadAll(r.Body); string(b) != "body" {
+		t.Errorf("unexpected body %q", b)
+	}
+}
+
+// The field of group by the dotted path, such as headers.User-Agent.
+func httpField(g map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		g, _ = g[k].(map[string]interface{})
+	}
+	v, ok := g[keys[len(keys)-1]]
+	return v, ok
+}
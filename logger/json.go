@@ -0,0 +1,338 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The built-in fields of JSON log in default order, where fields is the user fields.
+var defaultJSONOrder = []string{"ts", "level", "global", "pid", "seq", "cid", "goroutine", "delta", "topic", "prefix", "tags", "caller", "func", "fields", "msg"}
+
+// Set the order of built-in fields in JSON log, use "fields" for the position of user fields,
+// default to:
+//		ts, level, global, pid, seq, cid, goroutine, delta, topic, prefix, tags, caller, func, fields, msg
+// The fields not in order are written after, in the default order, and the unknown
+// fields are ignored, for example, to put msg after level:
+//		logger.SetJSONFieldOrder([]string{"ts", "level", "msg"})
+// Then the log is:
+//		{"ts":"2006-01-02T15:04:05.000000+08:00","level":"trace","msg":"message","pid":1,"cid":2,"key":"value"}
+// Set to nil to restore the default order.
+func SetJSONFieldOrder(order []string) {
+	updateOptions(WithJSONFieldOrder(order))
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// The style of JSON log, which decides the field names.
+type JSONStyle int
+
+const (
+	// The default style, see SetJSONFieldOrder.
+	StyleDefault JSONStyle = iota
+	// The Elastic Common Schema style, for example:
+	//		{"@timestamp":"2006-01-02T15:04:05.000000+08:00","log.level":"trace","message":"message","ecs.version":"1.6.0","process.pid":1,"cid":2,"key":"value"}
+	StyleECS
+)
+
+// Set the style of JSON log, StyleDefault or StyleECS.
+// For StyleECS, the fields are mapped to ECS names:
+//		ts to @timestamp
+//		level to log.level
+//		msg to message
+//		pid to process.pid
+//		caller to log.origin.file.name and log.origin.file.line
+//		func to log.origin.function
+//		seq to event.sequence
+// and the ecs.version is added, while the nested fields are flattened with dots, such as
+// request.method, so it lands in Elasticsearch without transformation.
+// @remark The order of fields is fixed for StyleECS, and SetJSONFieldOrder is ignored.
+func SetJSONStyle(style JSONStyle) {
+	updateOptions(WithJSONStyle(style))
+}
+
+// The layout of ts in JSON log, which is not changed by SetTimeFormat.
+const jsonTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// The layout of ts in JSON log, or of the sink, see SinkTimeFormat.
+func jsonTimeFormatOf(o *options) string {
+	if o.jsonTimeFormat != "" {
+		return o.jsonTimeFormat
+	}
+	return jsonTimeFormat
+}
+
+// The version of ECS for StyleECS.
+const ecsVersion = "1.6.0"
+
+// JSON log, for example:
+//		{"ts":"2006-01-02T15:04:05.000000+08:00","level":"trace","pid":1,"cid":2,"key":"value","msg":"message"}
+// The order of fields is stable, see SetJSONFieldOrder.
+func (v *loggerPlus) encodeJSON(b *bytes.Buffer, o *options, e *entry) {
+	t := v.timeOf(o, e)
+
+	b.WriteByte('{')
+	first := true
+	key := func(k string) {
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		encodeJSONString(b, k)
+		b.WriteByte(':')
+	}
+
+	if o.jsonStyle == StyleECS {
+		v.encodeECS(b, o, e, t, key)
+		b.WriteByte('}')
+		return
+	}
+
+	for _, name := range o.jsonOrder {
+		switch name {
+		case "ts":
+			key("ts")
+			encodeJSONString(b, t.Format(jsonTimeFormatOf(o)))
+		case "level":
+			key("level")
+			encodeJSONString(b, v.name())
+		case "global":
+			if o.globalPrefix != "" {
+				key(o.globalPrefixKey)
+				encodeJSONString(b, o.globalPrefix)
+			}
+		case "pid":
+			key("pid")
+			b.WriteString(strconv.Itoa(os.Getpid()))
+		case "seq":
+			if e.seq != 0 {
+				key("seq")
+				b.WriteString(strconv.FormatUint(e.seq, 10))
+			}
+		case "cid":
+			if cid, ok := cidOf(e.ctx); ok {
+				key("cid")
+				b.WriteString(strconv.Itoa(cid))
+			}
+		case "goroutine":
+			if e.goroutine != 0 {
+				key("goroutine")
+				b.WriteString(strconv.FormatUint(e.goroutine, 10))
+			}
+		case "delta":
+			if e.delta != "" {
+				key("delta")
+				encodeJSONString(b, e.delta)
+			}
+		case "topic":
+			if e.topic != "" {
+				key("topic")
+				encodeJSONString(b, e.topic)
+			}
+		case "prefix":
+			if e.prefix != "" {
+				key("prefix")
+				encodeJSONString(b, e.prefix)
+			}
+		case "tags":
+			if len(e.tags) > 0 {
+				key("tags")
+				encodeJSONTags(b, e.tags)
+			}
+		case "caller":
+			if e.caller != "" {
+				key("caller")
+				encodeJSONString(b, e.caller)
+			}
+		case "func":
+			if e.function != "" {
+				key("func")
+				encodeJSONString(b, e.function)
+			}
+		case "fields":
+			for _, f := range e.fields {
+				key(f.key)
+				encodeJSONValue(b, f.value)
+			}
+		case "msg":
+			key("msg")
+			encodeJSONString(b, e.jsonMessageOf(o))
+		}
+	}
+	b.WriteByte('}')
+}
+
+// The JSON log in ECS style, see StyleECS.
+func (v *loggerPlus) encodeECS(b *bytes.Buffer, o *options, e *entry, t time.Time, key func(k string)) {
+	key("@timestamp")
+	encodeJSONString(b, t.Format(jsonTimeFormatOf(o)))
+	key("log.level")
+	encodeJSONString(b, v.name())
+	key("message")
+	encodeJSONString(b, e.jsonMessageOf(o))
+	key("ecs.version")
+	encodeJSONString(b, ecsVersion)
+	if o.globalPrefix != "" {
+		key(o.globalPrefixKey)
+		encodeJSONString(b, o.globalPrefix)
+	}
+	key("process.pid")
+	b.WriteString(strconv.Itoa(os.Getpid()))
+	if e.seq != 0 {
+		key("event.sequence")
+		b.WriteString(strconv.FormatUint(e.seq, 10))
+	}
+
+	if cid, ok := cidOf(e.ctx); ok {
+		key("cid")
+		b.WriteString(strconv.Itoa(cid))
+	}
+	if e.goroutine != 0 {
+		key("goroutine")
+		b.WriteString(strconv.FormatUint(e.goroutine, 10))
+	}
+	if e.delta != "" {
+		key("delta")
+		encodeJSONString(b, e.delta)
+	}
+	if e.topic != "" {
+		key("topic")
+		encodeJSONString(b, e.topic)
+	}
+	if e.prefix != "" {
+		key("prefix")
+		encodeJSONString(b, e.prefix)
+	}
+	if len(e.tags) > 0 {
+		key("tags")
+		encodeJSONTags(b, e.tags)
+	}
+	if e.caller != "" {
+		file, line := e.caller, ""
+		if pos := strings.LastIndexByte(e.caller, ':'); pos > 0 {
+			file, line = e.caller[:pos], e.caller[pos+1:]
+		}
+		key("log.origin.file.name")
+		encodeJSONString(b, file)
+		if line != "" {
+			key("log.origin.file.line")
+			b.WriteString(line)
+		}
+	}
+	if e.function != "" {
+		key("log.origin.function")
+		encodeJSONString(b, e.function)
+	}
+
+	var flatten func(parent string, fields []field)
+	flatten = func(parent string, fields []field) {
+		for _, f := range fields {
+			if g, ok := f.value.(group); ok {
+				flatten(parent+f.key+".", g)
+				continue
+			}
+			key(parent + f.key)
+			encodeJSONValue(b, f.value)
+		}
+	}
+	flatten("", e.fields)
+}
+
+// Write the tags as JSON array, such as ["db","slow"].
+func encodeJSONTags(b *bytes.Buffer, tags []string) {
+	b.WriteByte('[')
+	for i, tag := range tags {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		encodeJSONString(b, tag)
+	}
+	b.WriteByte(']')
+}
+
+func encodeJSONValue(b *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case group:
+		b.WriteByte('{')
+		for i, f := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			encodeJSONString(b, f.key)
+			b.WriteByte(':')
+			encodeJSONValue(b, f.value)
+		}
+		b.WriteByte('}')
+	case jsonPayload:
+		b.Write(v)
+	case string:
+		encodeJSONString(b, v)
+	case error:
+		encodeJSONString(b, v.Error())
+	default:
+		if fn := loadOptions().jsonFormatter; fn != nil {
+			if data, ok := fn(v); ok {
+				b.Write(data)
+				return
+			}
+		}
+		if data, err := json.Marshal(v); err == nil {
+			b.Write(data)
+		} else {
+			encodeJSONString(b, fmt.Sprint(v))
+		}
+	}
+}
+
+const hex = "0123456789abcdef"
+
+// Write s as JSON string, escape the quote, backslash and control characters,
+// and replace the invalid UTF-8 by U+FFFD like encoding/json.
+func encodeJSONString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case c == '\n':
+				b.WriteString(`\n`)
+			case c == '\r':
+				b.WriteString(`\r`)
+			case c == '\t':
+				b.WriteString(`\t`)
+			case c < 0x20:
+				b.WriteString(`\u00`)
+				b.WriteByte(hex[c>>4])
+				b.WriteByte(hex[c&0xf])
+			default:
+				b.WriteByte(c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString("\ufffd")
+		} else {
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	b.WriteByte('"')
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+type testCidContext int
+
+func (v testCidContext) Cid() int {
+	return int(v)
+}
+
+func TestJSONFieldOrder(t *testing.T) {
+	e := &entry{
+		time:    time.Date(2020, 5, 11, 12, 30, 45, 123456000, time.UTC),
+		ctx:     testCidContext(100),
+		message: "The log text.",
+		fields:  []field{{"b", 1}, {"a", group{{"y", "x"}, {"x", true}}}},
+	}
+	pid := os.Getpid()
+
+	cases := []struct {
+		order  []string
+		expect string
+	}{
+		{nil, fmt.Sprintf(`{"ts":"2020-05-11T12:30:45.123456Z","level":"trace","pid":%v,"cid":100,"b":1,"a":{"y":"x","x":true},"msg":"The log text."}`, pid)},
+		{[]string{"msg", "level"}, fmt.Sprintf(`{"msg":"The log text.","level":"trace","ts":"2020-05-11T12:30:45.123456Z","pid":%v,"cid":100,"b":1,"a":{"y":"x","x":true}}`, pid)},
+		{[]string{"fields", "unknown", "ts", "fields"}, fmt.Sprintf(`{"b":1,"a":{"y":"x","x":true},"ts":"2020-05-11T12:30:45.123456Z","level":"trace","pid":%v,"cid":100,"msg":"The log text."}`, pid)},
+	}
+
+	v := newLoggerPlus(ioutil.Discard, LevelTrace)
+	for _, c := range cases {
+		SetJSONFieldOrder(c.order)
+
+		var b bytes.Buffer
+		v.encodeJSON(&b, loadOptions(), e)
+		if b.String() != c.expect {
+			t.Errorf("order %v, expect %v, actual %v", c.order, c.expect, b.String())
+		}
+	}
+	SetJSONFieldOrder(nil)
+}
+
+func TestJSONString(t *testing.T) {
+	var b bytes.Buffer
+	encodeJSONString(&b, "a\"b\\c\nd\x01\xffé")
+	if expect := `"a\"b\\c\nd\u0001` + "\ufffdé\""; b.String() != expect {
+		t.Errorf("expect %v, actual %v", expect, b.String())
+	}
+}
+
+func TestJSONStyleECS(t *testing.T) {
+	e := &entry{
+		time:     time.Date(2020, 5, 11, 12, 30, 45, 123456000, time.UTC),
+		ctx:      testCidContext(100),
+		message:  "The log text.",
+		fields:   []field{{"request", group{{"method", "GET"}, {"headers", group{{"Host", "ossrs.net"}}}}}},
+		caller:   "main.go:42",
+		function: "main.main",
+	}
+	expect := fmt.Sprintf(`{"@timestamp":"2020-05-11T12:30:45.123456Z","log.level":"trace","message":"The log text.",`+
+		`"ecs.version":"1.6.0","process.pid":%v,"cid":100,"log.origin.file.name":"main.go","log.origin.file.line":42,`+
+		`"log.origin.function":"main.main","request.method":"GET","request.headers.Host":"ossrs.net"}`, os.Getpid())
+
+	SetJSONStyle(StyleECS)
+	defer SetJSONStyle(StyleDefault)
+
+	var b bytes.Buffer
+	newLoggerPlus(ioutil.Discard, LevelTrace).encodeJSON(&b, loadOptions(), e)
+	if b.String() != expect {
+		t.Errorf("expect %v, actual %v", expect, b.String())
+	}
+}
+
+func TestDecodeEntry(t *testing.T) {
+	e := &entry{
+		time:      time.Date(2020, 5, 11, 12, 30, 45, 123456000, time.UTC),
+		ctx:       testCidContext(100),
+		message:   "The log text.",
+		fields:    []field{{"b", 1}, {"a", group{{"y", "x"}}}},
+		goroutine: 7,
+		topic:     "db",
+		caller:    "main.go:42",
+		function:  "main.main",
+	}
+
+	for _, style := range []JSONStyle{StyleDefault, StyleECS} {
+		SetJSONStyle(style)
+
+		var b bytes.Buffer
+		newLoggerPlus(ioutil.Discard, LevelWarn).encodeJSON(&b, loadOptions(), e)
+
+		d, err := DecodeEntry(b.Bytes())
+		if err != nil {
+			t.Errorf("style %v, decode %v failed, err is %v", style, b.String(), err)
+			continue
+		}
+		if !d.Time.Equal(e.time) || !d.HasLevel || d.Level != LevelWarn || d.Pid != os.Getpid() ||
+			!d.HasCid || d.Cid != 100 || d.Goroutine != 7 || d.Topic != "db" || d.Caller != "main.go:42" ||
+			d.Function != "main.main" || d.Message != "The log text." {
+			t.Errorf("style %v, decode %v, actual %+v", style, b.String(), d)
+		}
+		if fmt.Sprint(d.Fields["b"]) != "1" {
+			t.Errorf("style %v, decode %v, fields %v", style, b.String(), d.Fields)
+		}
+	}
+	SetJSONStyle(StyleDefault)
+
+	if _, err := DecodeEntry([]byte("[trace] message")); err == nil {
+		t.Error("expect error for text log")
+	}
+}
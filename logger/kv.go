@@ -0,0 +1,52 @@
+package logger
+
+import "fmt"
+
+// The key for the value without key in kv list, same to slog.
+const badKey = "!BADKEY"
+
+// Trace level log with fields of alternating key/value list, for example:
+//		logger.Tkv(ctx, "Serve request", "method", r.Method, "url", r.URL)
+// The text log is "Serve request method=GET url=/api", while the JSON log has
+// the fields "method" and "url". The value without string key, such as the
+// last one of odd-length list, is written with key !BADKEY rather than panic.
+func Tkv(ctx Context, message string, kv ...interface{}) {
+	printFields(Trace, ctx, message, kvFields(kv)...)
+}
+
+// Warn level log with fields of alternating key/value list, see Tkv.
+func Wkv(ctx Context, message string, kv ...interface{}) {
+	printFields(Warn, ctx, message, kvFields(kv)...)
+}
+
+// Error level log with fields of alternating key/value list, see Tkv.
+func Ekv(ctx Context, message string, kv ...interface{}) {
+	printFields(Error, ctx, message, kvFields(kv)...)
+}
+
+// Convert the alternating key/value list to fields, the fmt.Stringer key is
+// also accepted, while other values without key are fields of badKey.
+func kvFields(kv []interface{}) []field {
+	var fields []field
+	for len(kv) > 0 {
+		var key string
+		switch k := kv[0].(type) {
+		case string:
+			key = k
+		case fmt.Stringer:
+			key = k.String()
+		default:
+			fields = append(fields, field{badKey, kv[0]})
+			kv = kv[1:]
+			continue
+		}
+
+		if len(kv) == 1 {
+			fields = append(fields, field{badKey, key})
+			break
+		}
+		fields = append(fields, field{key, kv[1]})
+		kv = kv[2:]
+	}
+	return fields
+}
@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestKvFields(t *testing.T) {
+	var b bytes.Buffer
+	Switch(&b)
+	defer Switch(os.Stdout)
+
+	Tkv(nil, "Serve", "method", "GET", "size", 10, "odd")
+	if s := strings.TrimSpace(b.String()); !strings.HasSuffix(s, "Serve method=GET size=10 !BADKEY=odd") {
+		t.Errorf("invalid text log %v", s)
+	}
+
+	SetFormat(FormatJSON)
+	defer SetFormat(FormatText)
+
+	b.Reset()
+	Wkv(nil, "Serve", 404, "method", "GET")
+	if s := b.String(); !strings.Contains(s, `"!BADKEY":404,"method":"GET"`) {
+		t.Errorf("invalid json log %v", s)
+	}
+}
@@ -0,0 +1,26 @@
+package logger
+
+import "time"
+
+// The key of start time in context.
+type startKey struct{}
+
+// Stamp the start time in ctx, return the derived context to pass down, for example,
+// in the middleware of request:
+//		ctx = logger.ContextWithStart(ctx)
+//		defer logger.LogLatency(ctx, "handle request")
+// @remark The cid of ctx is kept, see PushPrefix.
+func ContextWithStart(ctx Context) Context {
+	return withValue(ctx, startKey{}, time.Now())
+}
+
+// Log the elapsed time since the start of ContextWithStart at Trace level, for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] handle request latency_ms=1.5
+// In JSON, the latency_ms is a number, or "unknown" if ctx has no start, see Timed.
+func LogLatency(ctx Context, label string) {
+	var latency interface{} = "unknown"
+	if start, ok := valueOf(ctx, startKey{}).(time.Time); ok {
+		latency = float64(time.Since(start)) / float64(time.Millisecond)
+	}
+	printFields(Trace, ctx, label, field{"latency_ms", latency})
+}
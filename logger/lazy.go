@@ -0,0 +1,41 @@
+package logger
+
+import "fmt"
+
+// The lazy message, evaluated only when formatted.
+type lazy func() string
+
+func (v lazy) String() string {
+	return v()
+}
+
+// Wrap fn as an arg which is only evaluated when the log is going to be written,
+// for expensive message such as a dump, for example:
+//		logger.I(ctx, "state is", logger.Lazy(func() string {
+//			return expensiveDump()
+//		}))
+// The fn is called when the message is formatted, which is after the level is checked,
+// so it's never called when the level is disabled, and it's called at most once for
+// each log, in the goroutine which writes the log.
+// @remark It's called if the log is buffered by SetFlushOnError, even it's discarded later.
+func Lazy(fn func() string) fmt.Stringer {
+	return lazy(fn)
+}
+
+// Replace the Lazy args by their messages, so each fn is called once, even the args are
+// formatted twice, such as for the Verbose args.
+func resolveLazyArgs(args []interface{}) []interface{} {
+	var resolved []interface{}
+	for i, arg := range args {
+		if fn, ok := arg.(lazy); ok {
+			if resolved == nil {
+				resolved = append([]interface{}(nil), args...)
+			}
+			resolved[i] = fn()
+		}
+	}
+	if resolved == nil {
+		return args
+	}
+	return resolved
+}
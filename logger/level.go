@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The level of log, from verbose to fatal.
+type Level int
+
+const (
+	// The verbose info level, see Info.
+	LevelInfo Level = iota
+	// The trace level, the default level, see Trace.
+	LevelTrace
+	// The warning level, see Warn.
+	LevelWarn
+	// The error level, see Error.
+	LevelError
+)
+
+// The label of each level, index by Level.
+var levelLabels = [...]string{logInfoLabel, logTraceLabel, logWarnLabel, logErrorLabel}
+
+// The name of level, such as trace for LevelTrace, or unknown for invalid level.
+func (v Level) String() string {
+	if v < LevelInfo || v > LevelError {
+		return "unknown"
+	}
+	return strings.Trim(levelLabels[v], "[] ")
+}
+
+// Parse the level from name, for config files and flags, for example:
+//		level, err := logger.ParseLevel(os.Getenv("LOG_LEVEL"))
+// The name is case-insensitive, and the aliases are accepted:
+//		info, verbose, debug to LevelInfo
+//		trace to LevelTrace
+//		warn, warning to LevelWarn
+//		error, err to LevelError
+// @remark It's the reverse of Level.String, so ParseLevel(level.String()) is level.
+func ParseLevel(s string) (Level, error) {
+	if level, ok := parseLevel(s); ok {
+		return level, nil
+	}
+	return LevelTrace, fmt.Errorf("invalid level %q", s)
+}
+
+func parseLevel(s string) (Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "info", "verbose", "debug":
+		return LevelInfo, true
+	case "trace":
+		return LevelTrace, true
+	case "warn", "warning":
+		return LevelWarn, true
+	case "error", "err":
+		return LevelError, true
+	}
+	return 0, false
+}
+
+// Set the minimum level to write, default to LevelTrace,
+// for example, set to LevelInfo to enable the verbose Info logs.
+func SetLevel(level Level) {
+	updateOptions(WithLevel(level))
+}
+
+// Set the matcher to decide the minimum level for each log by its context,
+// for example, write Trace logs for admin while only Warn logs for others:
+//		logger.SetLevelMatcher(func(ctx logger.Context) (logger.Level, bool) {
+//			if isAdmin(ctx) {
+//				return logger.LevelTrace, true
+//			}
+//			return logger.LevelWarn, true
+//		})
+// If the matcher returns false, use the level of SetLevel. Set to nil to remove it.
+// @remark The matcher is called for each log, so it should be fast.
+func SetLevelMatcher(matcher func(ctx Context) (Level, bool)) {
+	updateOptions(WithLevelMatcher(matcher))
+}
+
+// Set the threshold to escalate the logs of context near its deadline, default to 0 to disable,
+// for example, to diagnose the slow requests before they time out:
+//		logger.SetEscalateNearDeadline(100 * time.Millisecond)
+//		ctx, cancel := context.WithTimeout(ctx, time.Second)
+//		logger.I(ctx, "detail") // Written if within 100ms of the deadline, even Info is disabled.
+// When the context is within threshold of its deadline or has exceeded it, all levels
+// are written for it, regardless of SetLevel and SetLevelMatcher.
+// @remark It only works for context which has Deadline, such as context.Context.
+func SetEscalateNearDeadline(threshold time.Duration) {
+	updateOptions(WithEscalateNearDeadline(threshold))
+}
+
+// Boost the minimum level to write until restore, for example, to write the Trace
+// logs around a risky operation, even SetLevel to LevelWarn:
+//		func doRiskyThing() {
+//			defer logger.BoostLevel(logger.LevelTrace)()
+//			......
+//		}
+// The boost only lowers the minimum level, it never hides logs, and the nested or
+// concurrent boosts are counted, so the lowest one of active boosts is used.
+// @remark Go has no goroutine local storage, so the boost is global, which works
+// 	for all goroutines until restore, rather than the calling goroutine only.
+func BoostLevel(level Level) (restore func()) {
+	if level < LevelInfo {
+		level = LevelInfo
+	} else if level > LevelError {
+		level = LevelError
+	}
+
+	updateOptions(func(o *options) {
+		o.boosts[level]++
+	})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			updateOptions(func(o *options) {
+				o.boosts[level]--
+			})
+		})
+	}
+}
+
+// Whether ctx is near its deadline.
+func (v *options) nearDeadline(ctx Context) bool {
+	if v.escalateThreshold <= 0 {
+		return false
+	}
+	if ctx, ok := ctx.(interface{ Deadline() (time.Time, bool) }); ok {
+		if deadline, ok := ctx.Deadline(); ok {
+			return time.Until(deadline) <= v.escalateThreshold
+		}
+	}
+	return false
+}
+
+// The minimum level for ctx, by the deadline, the level matcher or the global level,
+// then lowered by the active boosts.
+func (v *options) levelOf(ctx Context) Level {
+	return v.topicLevelOf(ctx, "")
+}
+
+// The minimum level for ctx of topic, the level of topic overrides the level matcher
+// and the global level, see levelOf.
+func (v *options) topicLevelOf(ctx Context, topic string) Level {
+	if v.nearDeadline(ctx) {
+		return LevelInfo
+	}
+
+	level := v.level
+	if l, ok := v.topicLevels[topic]; ok && topic != "" {
+		level = l
+	} else if v.levelMatcher != nil {
+		if l, ok := v.levelMatcher(ctx); ok {
+			level = l
+		}
+	}
+
+	for l := LevelInfo; l < level && l <= LevelError; l++ {
+		if v.boosts[l] > 0 {
+			return l
+		}
+	}
+	return level
+}
+
+// The package logger of level, such as Trace for LevelTrace.
+func levelLogger(level Level) Logger {
+	switch {
+	case level <= LevelInfo:
+		return Info
+	case level == LevelTrace:
+		return Trace
+	case level == LevelWarn:
+		return Warn
+	default:
+		return Error
+	}
+}
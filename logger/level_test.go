@@ -0,0 +1,49 @@
+package logger
+
+import "testing"
+
+func TestParseLevel(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		level Level
+		ok    bool
+	}{
+		{"info", LevelInfo, true},
+		{"verbose", LevelInfo, true},
+		{"debug", LevelInfo, true},
+		{"trace", LevelTrace, true},
+		{"TRACE", LevelTrace, true},
+		{" warn ", LevelWarn, true},
+		{"warning", LevelWarn, true},
+		{"error", LevelError, true},
+		{"err", LevelError, true},
+		{"fatal", LevelTrace, false},
+		{"", LevelTrace, false},
+	} {
+		level, err := ParseLevel(c.name)
+		if (err == nil) != c.ok || level != c.level {
+			t.Errorf("parse %q expect %v %v, actual %v %v", c.name, c.level, c.ok, level, err)
+		}
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	for level := LevelInfo; level <= LevelError; level++ {
+		if r, err := ParseLevel(level.String()); err != nil || r != level {
+			t.Errorf("round trip %v, actual %v %v", level, r, err)
+		}
+	}
+	if s := Level(100).String(); s != "unknown" {
+		t.Errorf("invalid level %v", s)
+	}
+}
+
+func TestAsyncPolicyString(t *testing.T) {
+	for policy, expect := range map[AsyncPolicy]string{
+		PolicyBlock: "block", PolicyDropNew: "drop-new", PolicyDropOldest: "drop-oldest", AsyncPolicy(100): "unknown",
+	} {
+		if s := policy.String(); s != expect {
+			t.Errorf("policy %d, expect %v, actual %v", int(policy), expect, s)
+		}
+	}
+}
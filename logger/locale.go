@@ -0,0 +1,60 @@
+package logger
+
+import "strings"
+
+// The key of locale in context.
+type localeKey struct{}
+
+// Derive ctx with the locale of user, such as "zh-CN", to localize the messages of its
+// logs by the catalogs, see RegisterCatalog.
+// @remark The cid of ctx is kept, see PushPrefix.
+func ContextWithLocale(ctx Context, locale string) Context {
+	return withValue(ctx, localeKey{}, locale)
+}
+
+// Register the catalog of locale, which maps the message id to the localized template,
+// for the user-facing logs such as audit, for example:
+//		logger.RegisterCatalog("zh-CN", map[string]string{"user %v login": "用户 %v 登录"})
+//		ctx = logger.ContextWithLocale(ctx, "zh-CN")
+//		logger.Tf(ctx, "user %v login", name)
+// Then the format of Printf is the message id, which is replaced by the template of
+// locale of ctx, or of its language such as "zh" if not found. The message id is used
+// if no translation exists. Set catalog to nil to remove the locale.
+// @remark Only the format of Printf is localized, the args and fields are not.
+func RegisterCatalog(locale string, catalog map[string]string) {
+	copied := make(map[string]string, len(catalog))
+	for id, template := range catalog {
+		copied[id] = template
+	}
+
+	updateOptions(func(o *options) {
+		catalogs := make(map[string]map[string]string, len(o.catalogs)+1)
+		for k, v := range o.catalogs {
+			catalogs[k] = v
+		}
+		if catalog == nil {
+			delete(catalogs, locale)
+		} else {
+			catalogs[locale] = copied
+		}
+		o.catalogs = catalogs
+	})
+}
+
+// The localized template of message id for the locale of ctx, or id if not found.
+func localize(o *options, ctx Context, id string) string {
+	locale, _ := valueOf(ctx, localeKey{}).(string)
+	if locale == "" {
+		return id
+	}
+
+	if template, ok := o.catalogs[locale][id]; ok {
+		return template
+	}
+	if pos := strings.IndexAny(locale, "-_"); pos > 0 {
+		if template, ok := o.catalogs[locale[:pos]][id]; ok {
+			return template
+		}
+	}
+	return id
+}
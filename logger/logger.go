@@ -9,16 +9,22 @@
 //		logger.Wf(ctx, format, ...)
 //		logger.Ef(ctx, format, ...)
 // @remark the Context is optional thus can be nil.
+// @remark Build with -tags nologverbose to compile logger.I and logger.If to nothing.
 // @remark From 1.7+, the ctx could be context.Context, wrap by logger.WithContext,
 // 	please read ExampleLogger_ContextGO17().
 package logger
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // default level for logger.
@@ -39,86 +45,337 @@ type cidContext interface {
 	Cid() int
 }
 
+// The cid of context, false if no cid.
+func cidOf(ctx Context) (int, bool) {
+	if ctx, ok := ctx.(cidContext); ok {
+		return ctx.Cid(), true
+	}
+	return 0, false
+}
+
 // the LOG+ which provides connection-based log.
 type loggerPlus struct {
 	logger *log.Logger
+	level  Level
+	// Whether closed by Close, only checked for strict concurrency.
+	closed uint32
 }
 
+// Create logger by l, the level is parsed from the prefix such as "[warn] ",
+// default to LevelTrace if not a level label.
 func NewLoggerPlus(l *log.Logger) Logger {
-	return &loggerPlus{logger: l}
+	level := LevelTrace
+	for i, label := range levelLabels {
+		if l.Prefix() == label {
+			level = Level(i)
+		}
+	}
+	return &loggerPlus{logger: l, level: level}
 }
 
-func (v *loggerPlus) format(ctx Context, a ...interface{}) []interface{} {
-	if ctx == nil {
-		return append([]interface{}{fmt.Sprintf("[%v] ", os.Getpid())}, a...)
-	} else if ctx, ok := ctx.(cidContext); ok {
-		return append([]interface{}{fmt.Sprintf("[%v][%v] ", os.Getpid(), ctx.Cid())}, a...)
+// Create logger for level, write to w.
+func newLoggerPlus(w io.Writer, level Level) *loggerPlus {
+	l := log.New(w, levelLabels[level], log.Ldate|log.Ltime|log.Lmicroseconds)
+	return &loggerPlus{logger: l, level: level}
+}
+
+// @remark The message is formatted only when the log is going to be written, see Lazy.
+func (v *loggerPlus) Println(ctx Context, a ...interface{}) {
+	v.output(&entry{ctx: ctx, args: a, println: true})
+}
+
+// @remark The message is formatted only when the log is going to be written, see Lazy.
+func (v *loggerPlus) Printf(ctx Context, format string, a ...interface{}) {
+	v.output(&entry{ctx: ctx, format: format, args: a})
+}
+
+// @remark The fn is run after the level and sampling checks, so it's free for disabled level.
+func (v *loggerPlus) Do(ctx Context, fn func()) {
+	if v.enabled(loadOptions(), ctx, "") {
+		fn()
 	}
-	return a
 }
 
-func (v *loggerPlus) formatf(ctx Context, format string, a ...interface{}) (string, []interface{}) {
-	if ctx == nil {
-		return "[%v] " + format, append([]interface{}{os.Getpid()}, a...)
+// Whether the level of logger is enabled for ctx of topic.
+func (v *loggerPlus) enabled(o *options, ctx Context, topic string) bool {
+	return v.level >= o.topicLevelOf(ctx, topic) && (v.level >= LevelWarn || o.sampled(ctx)) && !muted(ctx, v.level)
+}
+
+// The prefix of text log, which identify the process and connection.
+// @remark The separator between prefix and message is not included, see SetPrefixSeparator.
+func (v *loggerPlus) prefix(o *options, ctx Context) string {
+	if ctx = baseContext(ctx); ctx == nil {
+		return fmt.Sprintf("[%v]", os.Getpid())
 	} else if ctx, ok := ctx.(cidContext); ok {
-		return "[%v][%v] " + format, append([]interface{}{os.Getpid(), ctx.Cid()}, a...)
+		return fmt.Sprintf("[%v]["+o.cidFormat+"]", os.Getpid(), ctx.Cid())
 	}
-	return format, a
+	return ""
+}
+
+// Set the format of cid in prefix of text log, default to %v, for example,
+// the fixed-width hex to correlate with packet captures:
+//		logger.SetCIDFormat("%08x") // The prefix is [pid][0000002a] for cid 42.
+// The format must contain exactly one verb for integer, or return error.
+// @remark The cid of JSON and protobuf log is always a number.
+func SetCIDFormat(format string) error {
+	if verbs := countVerbs(format); verbs != 1 {
+		return fmt.Errorf("cid format %q should have exactly one verb, got %v", format, verbs)
+	}
+	if s := fmt.Sprintf(format, 0); strings.Contains(s, "%!") {
+		return fmt.Errorf("cid format %q is invalid for integer, got %v", format, s)
+	}
+
+	updateOptions(func(o *options) {
+		o.cidFormat = format
+	})
+	return nil
+}
+
+// The level name of logger, for example, trace for label "[trace] ".
+func (v *loggerPlus) name() string {
+	return strings.Trim(v.logger.Prefix(), "[] ")
 }
 
 var colorYellow = "\033[33m"
 var colorRed = "\033[31m"
 var colorBlack = "\033[0m"
 
-func (v *loggerPlus) doPrintln(args ...interface{}) {
-	if previousIo == nil {
-		if v == Error {
-			fmt.Fprintf(os.Stdout, colorRed)
-			v.logger.Println(args...)
-			fmt.Fprintf(os.Stdout, colorBlack)
-		} else if v == Warn {
-			fmt.Fprintf(os.Stdout, colorYellow)
-			v.logger.Println(args...)
-			fmt.Fprintf(os.Stdout, colorBlack)
-		} else {
-			v.logger.Println(args...)
+// The scope of color for console.
+type ColorScope int
+
+const (
+	// Colorize the whole line, the default scope.
+	ScopeWholeLine ColorScope = iota
+	// Colorize only the level label such as [error], the time and message stay uncolored.
+	ScopeLabel
+)
+
+// Set the scope of color for console, ScopeWholeLine or ScopeLabel.
+func SetColorScope(scope ColorScope) {
+	updateOptions(WithColorScope(scope))
+}
+
+// The mode of color.
+type ColorMode int
+
+const (
+	// Colorize only if the writer is a terminal, the default mode.
+	ColorAuto ColorMode = iota
+	// Always colorize, even the writer is a file or pipe.
+	ColorAlways
+	// Never colorize.
+	ColorNever
+)
+
+// Set the mode of color, ColorAuto, ColorAlways or ColorNever.
+// For ColorAuto, each writer is checked, so the console is colorized while the file
+// is not, even the logs are written to both by AddSink.
+func SetColorMode(mode ColorMode) {
+	updateOptions(WithColorMode(mode))
+}
+
+// The color of logger for w, empty if no color, only for text log to console.
+func (v *loggerPlus) color(o *options, w io.Writer, format Format) string {
+	if format != FormatText || o.colorMode == ColorNever {
+		return ""
+	}
+	if o.colorMode == ColorAuto && !isTerminal(w) {
+		return ""
+	}
+	return v.levelColor()
+}
+
+// The color of level, red for Error and yellow for Warn, empty for others.
+func (v *loggerPlus) levelColor() string {
+	if v.level == LevelError {
+		return colorRed
+	} else if v.level == LevelWarn {
+		return colorYellow
+	}
+	return ""
+}
+
+// Lock for writing log, to avoid interleaved lines.
+var outputLock sync.Mutex
+
+// Encode the entry and write to the underlayer io.
+func (v *loggerPlus) output(e *entry) {
+	o := loadOptions()
+	if o.strictConcurrency && atomic.LoadUint32(&v.closed) != 0 {
+		strictViolation("log after Close")
+	}
+	if !v.enabled(o, e.ctx, e.topic) {
+		return
+	}
+
+	e.time = time.Now()
+	if o.measureOverhead {
+		start := e.time
+		defer func() {
+			atomic.AddUint64(&stats.overhead, uint64(time.Since(start)))
+		}()
+	}
+	if l := o.rateLimits[v.level]; l != nil && !e.uncounted {
+		ok, dropped := l.allow(e.time)
+		if !ok {
+			return
 		}
-	} else {
-		v.logger.Println(args...)
-	}
-}
-
-func (v *loggerPlus) doPrintf(format string, args ...interface{}) {
-	if previousIo == nil {
-		if v == Error {
-			fmt.Fprintf(os.Stdout, colorRed)
-			v.logger.Printf(format, args...)
-			fmt.Fprintf(os.Stdout, colorBlack)
-		} else if v == Warn {
-			fmt.Fprintf(os.Stdout, colorYellow)
-			v.logger.Printf(format, args...)
-			fmt.Fprintf(os.Stdout, colorBlack)
-		} else {
-			v.logger.Printf(format, args...)
+		if dropped > 0 {
+			v.output(&entry{message: "rate limit", fields: []field{{"dropped", dropped}}, uncounted: true})
+		}
+	}
+	if l := o.contextLimit; l != nil && !e.uncounted {
+		if ok, warn := l.allow(e.ctx, e.time); !ok {
+			if warn {
+				fields := []field{{"max", l.max}, {"window", l.window}}
+				printEntry(Warn, &entry{ctx: e.ctx, message: "this connection is too chatty", fields: fields, uncounted: true})
+			}
+			return
 		}
-	} else {
-		v.logger.Printf(format, args...)
+	}
+	if q := o.dailyQuota; q != nil && v.level < LevelError && !e.uncounted {
+		if ok, warn := q.allow(e.time, o.timeZone); !ok {
+			if warn {
+				printEntry(Warn, &entry{message: "daily quota exceeded", fields: []field{{"quota", q.limit}}, uncounted: true})
+			}
+			return
+		}
+	}
+
+	if t, ok := e.takeAtTime(); ok {
+		e.time = t
+	}
+	if len(o.catalogs) > 0 && e.format != "" {
+		e.format = localize(o, e.ctx, e.format)
+	}
+	if o.checkFormat && !e.uncounted {
+		checkFormat(e)
+	}
+	e.formatMessage(o)
+	if fields := contextFieldsOf(e.ctx); len(fields) > 0 {
+		e.appendMissingFields(fields)
+		e.resolveLazyFields()
+	}
+	if len(o.constantFields) > 0 {
+		e.appendMissingFields(o.constantFields)
+	}
+	if o.showVersion {
+		e.appendVersionFields()
+	}
+	e.tags = tagsOf(e.ctx)
+	e.prefix = prefixStackOf(e.ctx)
+	if o.prefixFunc != nil {
+		e.prefix += o.prefixFunc(e.ctx)
+	}
+	if o.showCaller {
+		e.caller, e.function = callerOf(e.pc, o.callerFunc, o.callerSkip)
+	}
+	if o.showGoroutineID {
+		e.goroutine = goroutineID()
+	}
+
+	// Fail after unlock, so the failure function is free to log.
+	var fatal string
+	defer func() {
+		if fatal != "" {
+			failError(o, fatal)
+		}
+	}()
+
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	if o.showDelta && !e.uncounted {
+		e.delta = deltaOf(e.ctx, e.time)
+	}
+
+	if o.flushOnError && !e.uncounted {
+		if cid, ok := cidOf(e.ctx); ok {
+			if v.level < LevelWarn {
+				bufferLine(cid, v, e)
+				return
+			} else if v.level == LevelError {
+				flushBuffered(o, cid)
+			}
+		}
+	}
+
+	v.writeEntry(o, e)
+
+	if o.errorIsFatal && v.level == LevelError && !e.uncounted {
+		fatal = strings.TrimSuffix(string(v.encode(o, e, FormatText, "")), "\n")
 	}
 }
 
-// Info, the verbose info level, very detail log, the lowest level, to discard.
-var Info Logger
+// Write the entry to the underlayer io, the sinks and the tee.
+// @remark The outputLock must be held.
+func (v *loggerPlus) writeEntry(o *options, e *entry) {
+	if o.showSequence {
+		e.seq = nextSequence()
+	}
 
-// Alias for Info level println.
-func I(ctx Context, a ...interface{}) {
-	Info.Println(ctx, a...)
+	// One buffer for all writers of entry, which is reset for each.
+	b := getBuffer()
+	defer putBuffer(b)
+
+	w := fallbackWriter(v.logger.Writer())
+	if o.format == FormatJSON && o.jsonStream == StyleArray {
+		b.WriteByte(frameJSONArray(w))
+	}
+	v.encodeTo(b, o, e, o.format, v.color(o, w, o.format), w)
+	kept, err := writeByWAL(o, v.level, w, b.Bytes())
+	err = checkBrokenPipe(w, err)
+	if q := o.dailyQuota; q != nil && err == nil && !kept {
+		q.rotate(e.time, o.timeZone)
+		q.add(b.Len())
+	}
+
+	switch {
+	case kept:
+		// Pending in WAL, which is counted when replayed.
+	case err != nil:
+		atomic.AddUint64(&stats.dropped, 1)
+	case !e.uncounted:
+		atomic.AddUint64(&stats.counts[v.level], 1)
+		countSummary(e.ctx, v.level)
+	}
+	if err != nil {
+		handleError(o, err)
+	}
+
+	if o.ringSize > 0 {
+		keepRing(o.ringSize, v.level, e.tags, v.encode(o, e, FormatText, ""))
+	}
+
+	if v.mirrored(o) && !sameWriter(w, os.Stderr) && !v.sinkedTo(o, os.Stderr) {
+		v.writeTo(o, os.Stderr, b, e, FormatText, v.color(o, os.Stderr, FormatText))
+	}
+
+	for _, s := range o.sinks {
+		if v.level >= s.level {
+			v.writeTo(s.optionsOf(o), s.w, b, e, s.format, v.color(o, s.w, s.format))
+		}
+	}
+
+	if w := teeWriter(e.ctx); w != nil {
+		v.writeTo(o, w, b, e, o.format, "")
+	}
 }
 
-// Printf for Info level log.
-func If(ctx Context, format string, a ...interface{}) {
-	Info.Printf(ctx, format, a...)
+// Encode the entry in buffer b and write to the extra writer, which is not counted in stats.
+func (v *loggerPlus) writeTo(o *options, w io.Writer, b *bytes.Buffer, e *entry, format Format, color string) {
+	b.Reset()
+	v.encodeTo(b, o, e, format, color, w)
+	if _, err := w.Write(b.Bytes()); err != nil {
+		handleError(o, err)
+	}
 }
 
+// Info, the verbose info level, very detail log, the lowest level, disabled by default.
+var Info Logger
+
+// @remark The I and If are removed when build with tag nologverbose, see verbose.go.
+
 // Trace, the trace level, something important, the default log level, to stdout.
 var Trace Logger
 
@@ -158,6 +415,54 @@ func Ef(ctx Context, format string, a ...interface{}) {
 	Error.Printf(ctx, format, a...)
 }
 
+// Drop-in replacement of fmt.Println, Trace level println with nil context, for example:
+//		fmt.Println("got", n, "bytes") // Before.
+//		logger.Print("got", n, "bytes") // After, with the timestamp and pid.
+// @remark It's the convenience for migration, use T with context for new code.
+func Print(a ...interface{}) {
+	Trace.Println(nil, a...)
+}
+
+// Drop-in replacement of fmt.Printf, Trace level printf with nil context, see Print.
+// @remark The trailing newline of format is trimmed, for the log always ends with a newline.
+func Printf(format string, a ...interface{}) {
+	Trace.Printf(nil, strings.TrimSuffix(format, "\n"), a...)
+}
+
+// Log the err at Error level if not nil, return whether logged, for example:
+//		logger.Eerr(ctx, f.Close())
+// Rather than the boilerplate:
+//		if err := f.Close(); err != nil {
+//			logger.E(ctx, err)
+//		}
+func Eerr(ctx Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	Error.Println(ctx, err)
+	return true
+}
+
+// Log the err at Error level and return it, for example:
+//		return logger.ErrLog(ctx, err)
+// @remark The nil err is not logged, and nil is returned.
+func ErrLog(ctx Context, err error) error {
+	Eerr(ctx, err)
+	return err
+}
+
+// Wrap the err with message by %w, log it at Error level and return the wrapped one, for example:
+//		return logger.ErrWrap(ctx, err, "open file")
+// @remark The nil err is not logged, and nil is returned.
+func ErrWrap(ctx Context, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("%v: %w", message, err)
+	Error.Println(ctx, err)
+	return err
+}
+
 // The logger for oryx.
 type Logger interface {
 	// Println for logger plus,
@@ -165,43 +470,282 @@ type Logger interface {
 	// 	or context.Context from GO1.7, or nil to ignore.
 	Println(ctx Context, a ...interface{})
 	Printf(ctx Context, format string, a ...interface{})
+	// Run fn only if the level is enabled for ctx, for example:
+	//		logger.Info.Do(ctx, func() {
+	//			logger.I(ctx, "checksum is", checksum(data))
+	//		})
+	Do(ctx Context, fn func())
 }
 
 func init() {
-	Info = NewLoggerPlus(log.New(ioutil.Discard, logInfoLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Trace = NewLoggerPlus(log.New(os.Stdout, logTraceLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Warn = NewLoggerPlus(log.New(os.Stdout, logWarnLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Error = NewLoggerPlus(log.New(os.Stdout, logErrorLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
+	Info = newLoggerPlus(os.Stdout, LevelInfo)
+	Trace = newLoggerPlus(os.Stdout, LevelTrace)
+	Warn = newLoggerPlus(os.Stdout, LevelWarn)
+	Error = newLoggerPlus(os.Stdout, LevelError)
 }
 
 // Switch the underlayer io.
 // @remark user must close previous io for logger never close it.
+// @remark Use SetLevel to enable the Info level, default to Trace.
+// @remark The ios opened by logger, such as SwitchPerLevelFiles, are closed by next Switch.
 func Switch(w io.Writer) {
-	// TODO: support level, default to trace here.
-	Info = NewLoggerPlus(log.New(ioutil.Discard, logInfoLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Trace = NewLoggerPlus(log.New(w, logTraceLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Warn = NewLoggerPlus(log.New(w, logWarnLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Error = NewLoggerPlus(log.New(w, logErrorLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-
+	switchIos([len(levelLabels)]io.Writer{w, w, w, w}, nil)
 	if w, ok := w.(io.Closer); ok {
-		previousIo = w
+		previousIos = []io.Closer{w}
 	}
 }
 
-// The previous underlayer io for logger.
-var previousIo io.Closer
+// Switch to w which is opened by logger, so it's closed by next Switch or Close.
+func switchOwned(w io.WriteCloser) {
+	switchIos([len(levelLabels)]io.Writer{w, w, w, w}, []io.Closer{w})
+}
+
+// Switch the underlayer io of each level, index by Level, and own the closers opened by
+// logger, then close the ones owned by previous switch.
+func switchIos(ws [len(levelLabels)]io.Writer, owned []io.Closer) {
+	Info = newLoggerPlus(ws[LevelInfo], LevelInfo)
+	Trace = newLoggerPlus(ws[LevelTrace], LevelTrace)
+	Warn = newLoggerPlus(ws[LevelWarn], LevelWarn)
+	Error = newLoggerPlus(ws[LevelError], LevelError)
+
+	previousIos = nil
+	if err := replaceOwnedIos(owned); err != nil {
+		handleError(loadOptions(), err)
+	}
+}
+
+// Set whether fail for each Error log after it's written, default to false, to surface
+// the unexpected errors in tests and CI, for example:
+//		logger.SetErrorIsFatal(true)
+//		defer logger.SetErrorIsFatal(false)
+// It panics with the error line, or calls the function of SetErrorFailFunc.
+// @remark Never enable it in production, it's only for tests.
+func SetErrorIsFatal(fatal bool) {
+	updateOptions(WithErrorIsFatal(fatal))
+}
+
+// Set the function to fail for Error log when SetErrorIsFatal, instead of panic, for example:
+//		logger.SetErrorFailFunc(func(line string) {
+//			t.Errorf("unexpected error log: %v", line)
+//		})
+// Set to nil to panic, which is the default.
+func SetErrorFailFunc(fail func(line string)) {
+	updateOptions(WithErrorFailFunc(fail))
+}
+
+// Fail for the Error log of line.
+func failError(o *options, line string) {
+	if o.errorFail != nil {
+		o.errorFail(line)
+		return
+	}
+	panic(fmt.Sprintf("logger: error is fatal: %v", line))
+}
+
+// Set the handler when write to the underlayer io failed, for example,
+// to fallback to stderr or increase a metric. Set to nil to report the error
+// to stderr, throttled by SetDiagnosticInterval, which is the default behavior.
+// @remark The handler is called in the goroutine which writes the log.
+func SetErrorHandler(h func(err error)) {
+	updateOptions(WithErrorHandler(h))
+}
+
+// The options of logger, which is copy-on-write,
+// so the log path only loads it once without lock.
+type options struct {
+	// The handler for error when write log, and the interval of diagnostics.
+	errorHandler func(err error)
+	diagInterval time.Duration
+	// The minimum level to write, and the matcher to decide it for each context.
+	level        Level
+	levelMatcher func(ctx Context) (Level, bool)
+	// The minimum level of each topic, see Topic.
+	topicLevels map[string]Level
+	// The rate of requests to write Info and Trace logs.
+	sampleRate float64
+	// The threshold to write all levels for context near its deadline.
+	escalateThreshold time.Duration
+	// The number of active BoostLevel, and the rate limiters, index by Level.
+	boosts     [len(levelLabels)]int
+	rateLimits [len(levelLabels)]*rateLimiter
+	// The limit of logs for each context.
+	contextLimit *contextLimiter
+	// The quota of bytes written per day.
+	dailyQuota *dailyQuota
+	// The format of log, text or json.
+	format Format
+	// The mode and scope of color for console.
+	colorMode  ColorMode
+	colorScope ColorScope
+	// The separator between prefix and message, and the format of cid, in text log.
+	prefixSeparator string
+	cidFormat       string
+	// The generator of cid for NewContext.
+	cidGenerator func() int
+	// Whether check the format of printf.
+	checkFormat bool
+	// The line ending of log.
+	lineEnding LineEnding
+	// Whether align the columns of text log.
+	columnAlign bool
+	// The layout, mode and timezone of time.
+	timeFormat string
+	timeMode   TimeMode
+	timeZone   *time.Location
+	// The layout of ts in JSON log, only for the sink with SinkTimeFormat.
+	jsonTimeFormat string
+	// The catalogs of messages by locale, see RegisterCatalog.
+	catalogs map[string]map[string]string
+	// The global prefix, and its key in JSON log.
+	globalPrefix, globalPrefixKey string
+	// The function to build extra prefix for context.
+	prefixFunc func(ctx Context) string
+	// The formatters of values, such as proto.Message.
+	textFormatter func(v interface{}) (string, bool)
+	jsonFormatter func(v interface{}) ([]byte, bool)
+	// Whether show the full representation of Verbose args in text log.
+	verbose bool
+	// Whether show the caller file:line, and its function name.
+	showCaller, callerFunc bool
+	// The frames to skip for the caller, see SetCallerSkip.
+	callerSkip int
+	// Whether show the id of goroutine, and the delta since previous log.
+	showGoroutineID, showDelta bool
+	// Whether show the sequence number of log.
+	showSequence bool
+	// Whether fail for Error logs, and the function to fail, panic if nil.
+	errorIsFatal bool
+	errorFail    func(line string)
+	// Whether buffer the Info and Trace logs of context, util an Error.
+	flushOnError bool
+	// The extra sinks besides the underlayer io.
+	sinks []*sink
+	// Whether mirror the Error logs to stderr, and the Warn logs.
+	mirrorErrors, mirrorWarns bool
+	// The size of ring to keep the last lines.
+	ringSize int
+	// The policy of async writer when queue is full.
+	asyncPolicy AsyncPolicy
+	// The constant fields for every log, and whether show the build version.
+	constantFields []field
+	showVersion    bool
+	// The order of built-in fields, and the style of JSON log.
+	jsonOrder  []string
+	jsonStyle  JSONStyle
+	jsonStream JSONStreamStyle
+	// The allowed headers and max body bytes for HTTP log.
+	httpHeaders   []string
+	httpBodyLimit int
+	// Whether log the args of SQL, and the threshold of slow SQL.
+	sqlArgs          bool
+	sqlSlowThreshold time.Duration
+	// The function to map HTTP status to level.
+	statusLevel func(status int) Level
+	// The level of Summary.
+	summaryLevel Level
+	// Whether fold the identical prefix of consecutive lines.
+	foldPrefix bool
+	// Whether escape the control characters in message.
+	escapeControl bool
+	// Whether measure the time spent in logger.
+	measureOverhead bool
+	// Whether check the misuse of logger.
+	strictConcurrency bool
+}
+
+var optionsLock sync.Mutex
+var optionsValue atomic.Value
+
+func init() {
+	optionsValue.Store(&options{
+		level:           LevelTrace,
+		sampleRate:      1,
+		diagInterval:    5 * time.Second,
+		prefixSeparator: " ",
+		cidFormat:       "%v",
+		globalPrefixKey: "global_prefix",
+		jsonOrder:       defaultJSONOrder,
+		httpHeaders:     []string{"Content-Type", "Content-Length", "User-Agent"},
+		summaryLevel:    LevelTrace,
+	})
+}
+
+func loadOptions() *options {
+	return optionsValue.Load().(*options)
+}
+
+func updateOptions(fn func(o *options)) {
+	optionsLock.Lock()
+	defer optionsLock.Unlock()
+
+	o := *loadOptions()
+	fn(&o)
+	optionsValue.Store(&o)
+}
+
+// The previous underlayer io of Switch, which is closed by Close.
+var previousIos []io.Closer
+
+// The underlayer ios opened by logger, such as the files of SwitchPerLevelFiles,
+// which are closed by next switch and Close, protected by outputLock.
+var ownedIos []io.Closer
+
+// Own the closers, and close the previous owned ones, return the first error.
+// @remark They're closed under outputLock, so no log is being written to them.
+func replaceOwnedIos(owned []io.Closer) (err error) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	for _, w := range ownedIos {
+		if r := w.Close(); r != nil && err == nil {
+			err = r
+		}
+	}
+	ownedIos = owned
+	return
+}
 
 // The interface io.Closer
 // Cleanup the logger, discard any log util switch to fresh writer.
+// @remark The heartbeat and auto flush are stopped, the restore of EnableVerboseFor is canceled,
+// 	the Flusher writers are flushed, and the sinks are removed and closed.
 func Close() (err error) {
-	Info = NewLoggerPlus(log.New(ioutil.Discard, logInfoLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Trace = NewLoggerPlus(log.New(ioutil.Discard, logTraceLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Warn = NewLoggerPlus(log.New(ioutil.Discard, logWarnLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-	Error = NewLoggerPlus(log.New(ioutil.Discard, logErrorLabel, log.Ldate|log.Ltime|log.Lmicroseconds))
-
-	if previousIo != nil {
-		err = previousIo.Close()
-		previousIo = nil
+	SetHeartbeat(0)
+	cancelVerboseFor()
+	SetAutoFlush(0)
+	if r := flushWriters(); r != nil {
+		err = r
+	}
+	if r := removeSinks(); r != nil {
+		err = r
+	}
+
+	outputLock.Lock()
+	closeJSONArray(nil)
+	if r := closeWAL(); r != nil {
+		err = r
+	}
+	outputLock.Unlock()
+
+	for _, l := range []Logger{Info, Trace, Warn, Error} {
+		markClosed(l)
+	}
+	Info = newLoggerPlus(ioutil.Discard, LevelInfo)
+	Trace = newLoggerPlus(ioutil.Discard, LevelTrace)
+	Warn = newLoggerPlus(ioutil.Discard, LevelWarn)
+	Error = newLoggerPlus(ioutil.Discard, LevelError)
+	for _, l := range []Logger{Info, Trace, Warn, Error} {
+		markClosed(l)
+	}
+
+	for _, w := range previousIos {
+		if r := w.Close(); r != nil {
+			err = r
+		}
+	}
+	previousIos = nil
+	if r := replaceOwnedIos(nil); r != nil {
+		err = r
 	}
 
 	return
@@ -6,7 +6,7 @@ import (
 	ol "github.com/cheenwe/learn-go/logger"
 )
 
-func ExampleLogger_ToConsole() {
+func ExampleLogger_toConsole() {
 	// Simply log to console.
 	ol.Info.Println(nil, "The log text.")
 	ol.Trace.Println(nil, "The log text.")
@@ -26,7 +26,7 @@ func ExampleLogger_ToConsole() {
 	ol.Ef(nil, "The log %v", "text")
 }
 
-func ExampleLogger_ToFile() {
+func ExampleLogger_toFile() {
 	// Open logger file and change the tank for logger.
 	var err error
 	var f *os.File
@@ -42,7 +42,7 @@ func ExampleLogger_ToFile() {
 	defer ol.Close()
 }
 
-func ExampleLogger_SwitchFile() {
+func ExampleLogger_switchFile() {
 	// Initialize logger with file.
 	var err error
 	var f *os.File
@@ -74,7 +74,7 @@ func (v cidContext) Cid() int {
 	return int(v)
 }
 
-func ExampleLogger_ConnectionBased() {
+func ExampleLogger_connectionBased() {
 	ctx := cidContext(100)
 	ol.Info.Println(ctx, "The log text")
 	ol.Trace.Println(ctx, "The log text.")
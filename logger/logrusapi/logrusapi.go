@@ -0,0 +1,209 @@
+// The logrusapi package is a shim of the logrus-style API atop logger, to migrate from
+// logrus with minimal churn, for example, replace the import of logrus by:
+//		log "github.com/cheenwe/learn-go/logger/logrusapi"
+//		log.WithField("user", 42).Infof("login from %v", addr)
+// The logs are written by logger, so the sinks and formats of logger are used.
+// The intentional differences from logrus:
+//		The levels are mapped to logger as Trace and Debug to Info, Info to Trace,
+//		while Warn and Error are the same, see logger.Level.
+//		The cid is from the context of logger, set by Entry.WithContext.
+//		There is no Fatal or Panic, use logger.RecoverAndLog and os.Exit explicitly.
+package logrusapi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cheenwe/learn-go/logger"
+)
+
+// The fields of entry, same to logrus.Fields.
+type Fields map[string]interface{}
+
+// The entry with fields and context, which is immutable, so it's safe to share.
+type Entry struct {
+	ctx    logger.Context
+	fields Fields
+}
+
+// Create an entry with the field.
+func WithField(key string, value interface{}) *Entry {
+	return (&Entry{}).WithField(key, value)
+}
+
+// Create an entry with the fields.
+func WithFields(fields Fields) *Entry {
+	return (&Entry{}).WithFields(fields)
+}
+
+// Create an entry with the error field.
+func WithError(err error) *Entry {
+	return (&Entry{}).WithError(err)
+}
+
+// Create an entry with the context of logger, for the cid.
+func WithContext(ctx logger.Context) *Entry {
+	return (&Entry{}).WithContext(ctx)
+}
+
+// Derive the entry with the field.
+func (v *Entry) WithField(key string, value interface{}) *Entry {
+	return v.WithFields(Fields{key: value})
+}
+
+// Derive the entry with the fields, which override the fields of entry with the same key.
+func (v *Entry) WithFields(fields Fields) *Entry {
+	merged := make(Fields, len(v.fields)+len(fields))
+	for k, value := range v.fields {
+		merged[k] = value
+	}
+	for k, value := range fields {
+		merged[k] = value
+	}
+	return &Entry{ctx: v.ctx, fields: merged}
+}
+
+// Derive the entry with the error field.
+func (v *Entry) WithError(err error) *Entry {
+	return v.WithField("error", err)
+}
+
+// Derive the entry with the context of logger, for the cid.
+func (v *Entry) WithContext(ctx logger.Context) *Entry {
+	return &Entry{ctx: ctx, fields: v.fields}
+}
+
+// The context with the fields of entry, sorted by key.
+func (v *Entry) context() logger.Context {
+	keys := make([]string, 0, len(v.fields))
+	for k := range v.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ctx := v.ctx
+	for _, k := range keys {
+		ctx = logger.WithField(ctx, k, v.fields[k])
+	}
+	return ctx
+}
+
+// Log the message by printf with the fields of entry, if l is enabled for the context,
+// so the fields and message are only formatted when the log is going to be written.
+func (v *Entry) log(l logger.Logger, printf func(ctx logger.Context, format string, a ...interface{}), message func() string) {
+	l.Do(v.ctx, func() {
+		printf(v.context(), "%v", logger.Lazy(message))
+	})
+}
+
+// Log at Info level of logger, which is the most verbose level.
+// @remark It's removed when build with tag nologverbose, same to logger.If.
+func (v *Entry) Tracef(format string, args ...interface{}) {
+	v.log(logger.Info, logger.If, func() string {
+		return fmt.Sprintf(format, args...)
+	})
+}
+
+// Log at Info level of logger, same to Tracef.
+func (v *Entry) Debugf(format string, args ...interface{}) {
+	v.log(logger.Info, logger.If, func() string {
+		return fmt.Sprintf(format, args...)
+	})
+}
+
+// Log at Trace level of logger, which is the default level.
+func (v *Entry) Infof(format string, args ...interface{}) {
+	v.log(logger.Trace, logger.Tf, func() string {
+		return fmt.Sprintf(format, args...)
+	})
+}
+
+func (v *Entry) Warnf(format string, args ...interface{}) {
+	v.log(logger.Warn, logger.Wf, func() string {
+		return fmt.Sprintf(format, args...)
+	})
+}
+
+func (v *Entry) Errorf(format string, args ...interface{}) {
+	v.log(logger.Error, logger.Ef, func() string {
+		return fmt.Sprintf(format, args...)
+	})
+}
+
+// Log at Info level of logger, same to Tracef.
+func (v *Entry) Trace(args ...interface{}) {
+	v.log(logger.Info, logger.If, func() string {
+		return fmt.Sprint(args...)
+	})
+}
+
+func (v *Entry) Debug(args ...interface{}) {
+	v.log(logger.Info, logger.If, func() string {
+		return fmt.Sprint(args...)
+	})
+}
+
+func (v *Entry) Info(args ...interface{}) {
+	v.log(logger.Trace, logger.Tf, func() string {
+		return fmt.Sprint(args...)
+	})
+}
+
+func (v *Entry) Warn(args ...interface{}) {
+	v.log(logger.Warn, logger.Wf, func() string {
+		return fmt.Sprint(args...)
+	})
+}
+
+func (v *Entry) Error(args ...interface{}) {
+	v.log(logger.Error, logger.Ef, func() string {
+		return fmt.Sprint(args...)
+	})
+}
+
+// The entry without fields and context, for the package functions.
+var std = &Entry{}
+
+// Log at Info level of logger, which is the most verbose level.
+func Tracef(format string, args ...interface{}) {
+	std.Tracef(format, args...)
+}
+
+// Log at Info level of logger, same to Tracef.
+func Debugf(format string, args ...interface{}) {
+	std.Debugf(format, args...)
+}
+
+// Log at Trace level of logger, which is the default level.
+func Infof(format string, args ...interface{}) {
+	std.Infof(format, args...)
+}
+
+func Warnf(format string, args ...interface{}) {
+	std.Warnf(format, args...)
+}
+
+func Errorf(format string, args ...interface{}) {
+	std.Errorf(format, args...)
+}
+
+// Log at Info level of logger, same to Tracef.
+func Trace(args ...interface{}) {
+	std.Trace(args...)
+}
+
+func Debug(args ...interface{}) {
+	std.Debug(args...)
+}
+
+func Info(args ...interface{}) {
+	std.Info(args...)
+}
+
+func Warn(args ...interface{}) {
+	std.Warn(args...)
+}
+
+func Error(args ...interface{}) {
+	std.Error(args...)
+}
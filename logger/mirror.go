@@ -0,0 +1,35 @@
+package logger
+
+import "io"
+
+// Set whether mirror the Error logs to stderr, default to false, even the logs are
+// switched to a file, for example, to show errors in systemd status:
+//		logger.Switch(f)
+//		logger.SetMirrorErrorsToStderr(true)
+// The mirrored logs are always text, and not written again if the underlayer io is stderr,
+// or stderr is a sink of the level, see AddSink.
+// They're colored by SetColorMode for stderr, so no color if it's not a terminal.
+// @remark Use SetMirrorWarnsToStderr to also mirror the Warn logs.
+func SetMirrorErrorsToStderr(mirror bool) {
+	updateOptions(WithMirrorErrorsToStderr(mirror))
+}
+
+// Set whether mirror the Warn logs to stderr, see SetMirrorErrorsToStderr.
+func SetMirrorWarnsToStderr(mirror bool) {
+	updateOptions(WithMirrorWarnsToStderr(mirror))
+}
+
+// Whether the log of logger is written to w by any sink.
+func (v *loggerPlus) sinkedTo(o *options, w io.Writer) bool {
+	for _, s := range o.sinks {
+		if v.level >= s.level && sameWriter(s.w, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether mirror the log of logger to stderr.
+func (v *loggerPlus) mirrored(o *options) bool {
+	return v.level == LevelError && o.mirrorErrors || v.level == LevelWarn && o.mirrorWarns
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMirrorSkipStderrSink(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() {
+		os.Stderr = stderr
+	}()
+
+	var buf bytes.Buffer
+	Switch(&buf)
+	defer Switch(os.Stdout)
+	SetMirrorErrorsToStderr(true)
+	defer SetMirrorErrorsToStderr(false)
+	AddSink(os.Stderr, FormatText, LevelError)
+	defer removeSinks()
+
+	E(nil, "boom")
+	W(nil, "careful")
+	w.Close()
+
+	b, _ := ioutil.ReadAll(r)
+	if s := string(b); strings.Count(s, "boom") != 1 || strings.Contains(s, "careful") {
+		t.Errorf("unexpected stderr %q", s)
+	}
+	if !strings.Contains(buf.String(), "boom") {
+		t.Errorf("unexpected log %q", buf.String())
+	}
+}
@@ -0,0 +1,29 @@
+package logger
+
+// The key of mute in context, the value is the level to keep, such as LevelError.
+type muteKey struct{}
+
+// Derive ctx to mute all logs, for the health checks or internal polls which share code
+// with real requests, for example:
+//		if r.URL.Path == "/healthz" {
+//			ctx = logger.ContextMute(ctx, false)
+//		}
+// If keepErrors, the Error logs are still written for safety, so the failures of health
+// checks are not hidden. The logs are dropped before formatting, so it's free.
+// @remark The cid of ctx is kept, see PushPrefix.
+func ContextMute(ctx Context, keepErrors bool) Context {
+	keep := LevelError + 1
+	if keepErrors {
+		keep = LevelError
+	}
+	return withValue(ctx, muteKey{}, keep)
+}
+
+// Whether the log of level is muted for ctx.
+func muted(ctx Context, level Level) bool {
+	if ctx == nil {
+		return false
+	}
+	keep, ok := valueOf(ctx, muteKey{}).(Level)
+	return ok && level < keep
+}
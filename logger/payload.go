@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The max bytes of JSON payload in LogJSON, to cap the size of log.
+const maxJSONPayload = 4096
+
+// The JSON payload, which is nested in JSON log, while indented lines in text log.
+type jsonPayload []byte
+
+// The indented lines of payload.
+func (v jsonPayload) lines() lines {
+	var b bytes.Buffer
+	if err := json.Indent(&b, v, "", "  "); err != nil {
+		return lines{string(v)}
+	}
+	return strings.Split(b.String(), "\n")
+}
+
+// Log the v as JSON at level, for debugging the API payloads, for example:
+//		logger.LogJSON(ctx, logger.LevelTrace, "response", resp)
+// Then the text log is indented lines:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] response json:
+//		    {
+//		      "code": 0
+//		    }
+// In JSON, the json field is a nested object, so it's queryable.
+// @remark The JSON larger than 4KB is truncated as a string, and if failed to marshal,
+// 	the error and the %v of v are logged instead.
+func LogJSON(ctx Context, level Level, label string, v interface{}) {
+	l := levelLogger(level)
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		printFields(l, ctx, label, field{"error", err}, field{"value", fmt.Sprintf("%v", v)})
+		return
+	}
+
+	if len(data) > maxJSONPayload {
+		printFields(l, ctx, label, field{"json", string(data[:maxJSONPayload]) + "..."})
+		return
+	}
+	printFields(l, ctx, label, field{"json", jsonPayload(data)})
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// The options of SwitchPerLevelFiles.
+type perLevelOptions struct {
+	// Reopen the files when got it, nil to disable.
+	reopenSignal os.Signal
+}
+
+// The option of SwitchPerLevelFiles.
+type PerLevelOption func(o *perLevelOptions)
+
+// Reopen the log files of each level when got sig, which is compatible with logrotate
+// without copytruncate, for example:
+//		logger.SwitchPerLevelFiles("/var/log/app", logger.ReopenOnSignal(syscall.SIGHUP))
+// See InstallReopen.
+func ReopenOnSignal(sig os.Signal) PerLevelOption {
+	return func(o *perLevelOptions) {
+		o.reopenSignal = sig
+	}
+}
+
+// Switch to the log files of each level in dir, which is created if not exists, for example:
+//		if err := logger.SwitchPerLevelFiles("/var/log/app"); err != nil {
+//			return err
+//		}
+//		defer logger.Close()
+// Then the logs of each level are written to info.log, trace.log, warn.log and error.log.
+// The files are opened in append mode, so they can be rotated by logrotate with copytruncate,
+// or by logrotate with ReopenOnSignal, or on demand by Rotate which rotates each file.
+// @remark Return error and keep the current io if failed to create dir or open any file.
+// @remark Close or next Switch closes all the files.
+func SwitchPerLevelFiles(dir string, opts ...PerLevelOption) error {
+	var o perLevelOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var files [len(levelLabels)]io.Writer
+	var owned []io.Closer
+	for level := LevelInfo; level <= LevelError; level++ {
+		f, err := newReopenFile(filepath.Join(dir, level.String()+".log"), o.reopenSignal)
+		if err != nil {
+			for _, f := range owned {
+				f.Close()
+			}
+			return err
+		}
+		files[level] = f
+		owned = append(owned, f)
+	}
+
+	switchIos(files, owned)
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+// +build !unix,!windows
+
+package logger
+
+func isBrokenPipe(err error) bool {
+	return false
+}
+
+func notifyBrokenPipe() {
+}
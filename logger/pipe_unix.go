@@ -0,0 +1,20 @@
+//go:build unix
+// +build unix
+
+package logger
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE)
+}
+
+// Receive SIGPIPE, then writing to broken stdout returns EPIPE instead of exit.
+func notifyBrokenPipe() {
+	signal.Notify(make(chan os.Signal, 1), syscall.SIGPIPE)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package logger
+
+import (
+	"errors"
+	"syscall"
+)
+
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.ERROR_BROKEN_PIPE) || errors.Is(err, syscall.Errno(232)) // ERROR_NO_DATA
+}
+
+// There is no SIGPIPE on windows.
+func notifyBrokenPipe() {
+}
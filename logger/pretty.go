@@ -0,0 +1,273 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// The placeholder of the values deeper than max depth, and of the cycles.
+const prettyElided, prettyCycle = "…", "<cycle>"
+
+// The arg which renders v up to max depth.
+type prettyArg struct {
+	v        interface{}
+	maxDepth int
+}
+
+// Wrap v as an arg which renders up to maxDepth levels, for the deeply nested structs, for example:
+//		logger.T(ctx, "config is", logger.Pretty(cfg, 2))
+// Then the log is like %+v, while the deeper levels are replaced by …, for example:
+//		config is &{Name:app Upstream:{Host:a.com Pool:…}}
+// In JSON fields, such as Tkv, it's a nested object of exported fields, truncated as "…".
+// @remark The cycles, such as a node points to its parent, are rendered as <cycle>.
+func Pretty(v interface{}, maxDepth int) interface{} {
+	return prettyArg{v: v, maxDepth: maxDepth}
+}
+
+func (v prettyArg) String() string {
+	p := prettyPrinter{maxDepth: v.maxDepth, visited: make(map[uintptr]bool)}
+	p.text(reflect.ValueOf(v.v), 0)
+	return p.b.String()
+}
+
+func (v prettyArg) MarshalJSON() ([]byte, error) {
+	p := prettyPrinter{maxDepth: v.maxDepth, visited: make(map[uintptr]bool)}
+	p.json(reflect.ValueOf(v.v), 0)
+	return p.b.Bytes(), nil
+}
+
+// The printer to walk the value by reflection.
+type prettyPrinter struct {
+	b        bytes.Buffer
+	maxDepth int
+	// The pointers on current path, to detect the cycles.
+	visited map[uintptr]bool
+}
+
+// Whether v is a container, which takes a level of depth.
+func prettyContainer(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// Enter the pointer of v, return false if it's a cycle, and the leave function.
+func (v *prettyPrinter) enter(rv reflect.Value) (bool, func()) {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return true, func() {}
+		}
+		p := rv.Pointer()
+		if v.visited[p] {
+			return false, nil
+		}
+		v.visited[p] = true
+		return true, func() { delete(v.visited, p) }
+	}
+	return true, func() {}
+}
+
+// The value by Stringer or error, false if not.
+func prettyString(rv reflect.Value) (string, bool) {
+	if !rv.CanInterface() {
+		return "", false
+	}
+	switch x := rv.Interface().(type) {
+	case error:
+		return x.Error(), true
+	case fmt.Stringer:
+		return x.String(), true
+	}
+	return "", false
+}
+
+func (v *prettyPrinter) text(rv reflect.Value, depth int) {
+	if !rv.IsValid() {
+		v.b.WriteString("<nil>")
+		return
+	}
+	if s, ok := prettyString(rv); ok && rv.Kind() != reflect.Ptr && rv.Kind() != reflect.Interface {
+		v.b.WriteString(s)
+		return
+	}
+	if prettyContainer(rv) && depth >= v.maxDepth {
+		v.b.WriteString(prettyElided)
+		return
+	}
+
+	ok, leave := v.enter(rv)
+	if !ok {
+		v.b.WriteString(prettyCycle)
+		return
+	}
+	defer leave()
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			v.b.WriteString("<nil>")
+			return
+		}
+		if prettyContainer(rv.Elem()) && depth < v.maxDepth {
+			v.b.WriteByte('&')
+		}
+		v.text(rv.Elem(), depth)
+	case reflect.Interface:
+		v.text(rv.Elem(), depth)
+	case reflect.Struct:
+		v.b.WriteByte('{')
+		for i := 0; i < rv.NumField(); i++ {
+			if i > 0 {
+				v.b.WriteByte(' ')
+			}
+			v.b.WriteString(rv.Type().Field(i).Name)
+			v.b.WriteByte(':')
+			v.text(rv.Field(i), depth+1)
+		}
+		v.b.WriteByte('}')
+	case reflect.Map:
+		v.b.WriteString("map[")
+		for i, k := range prettyKeys(rv) {
+			if i > 0 {
+				v.b.WriteByte(' ')
+			}
+			v.text(k, depth+1)
+			v.b.WriteByte(':')
+			v.text(rv.MapIndex(k), depth+1)
+		}
+		v.b.WriteByte(']')
+	case reflect.Slice, reflect.Array:
+		v.b.WriteByte('[')
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				v.b.WriteByte(' ')
+			}
+			v.text(rv.Index(i), depth+1)
+		}
+		v.b.WriteByte(']')
+	default:
+		v.b.WriteString(prettyScalar(rv))
+	}
+}
+
+func (v *prettyPrinter) json(rv reflect.Value, depth int) {
+	if !rv.IsValid() {
+		v.b.WriteString("null")
+		return
+	}
+	if rv.Kind() != reflect.Ptr && rv.Kind() != reflect.Interface {
+		if s, ok := prettyString(rv); ok {
+			encodeJSONString(&v.b, s)
+			return
+		}
+	}
+	if prettyContainer(rv) && depth >= v.maxDepth {
+		encodeJSONString(&v.b, prettyElided)
+		return
+	}
+
+	ok, leave := v.enter(rv)
+	if !ok {
+		encodeJSONString(&v.b, prettyCycle)
+		return
+	}
+	defer leave()
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			v.b.WriteString("null")
+			return
+		}
+		v.json(rv.Elem(), depth)
+	case reflect.Struct:
+		v.b.WriteByte('{')
+		first := true
+		for i := 0; i < rv.NumField(); i++ {
+			if f := rv.Type().Field(i); f.PkgPath == "" {
+				if !first {
+					v.b.WriteByte(',')
+				}
+				first = false
+				encodeJSONString(&v.b, f.Name)
+				v.b.WriteByte(':')
+				v.json(rv.Field(i), depth+1)
+			}
+		}
+		v.b.WriteByte('}')
+	case reflect.Map:
+		if rv.IsNil() {
+			v.b.WriteString("null")
+			return
+		}
+		v.b.WriteByte('{')
+		for i, k := range prettyKeys(rv) {
+			if i > 0 {
+				v.b.WriteByte(',')
+			}
+			encodeJSONString(&v.b, prettyScalar(k))
+			v.b.WriteByte(':')
+			v.json(rv.MapIndex(k), depth+1)
+		}
+		v.b.WriteByte('}')
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			v.b.WriteString("null")
+			return
+		}
+		v.b.WriteByte('[')
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				v.b.WriteByte(',')
+			}
+			v.json(rv.Index(i), depth+1)
+		}
+		v.b.WriteByte(']')
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.b.WriteString(prettyScalar(rv))
+	case reflect.Float32, reflect.Float64:
+		if data, err := json.Marshal(rv.Float()); err == nil {
+			v.b.Write(data)
+		} else {
+			encodeJSONString(&v.b, prettyScalar(rv))
+		}
+	default:
+		encodeJSONString(&v.b, prettyScalar(rv))
+	}
+}
+
+// The keys of map, sorted by text.
+func prettyKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return prettyScalar(keys[i]) < prettyScalar(keys[j])
+	})
+	return keys
+}
+
+// The text of scalar, which works for unexported fields.
+func prettyScalar(rv reflect.Value) string {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	case reflect.String:
+		return rv.String()
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Ptr:
+		return fmt.Sprintf("%#x", rv.Pointer())
+	}
+	return fmt.Sprintf("%v", rv)
+}
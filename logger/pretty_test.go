@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestPretty(t *testing.T) {
+	type node struct {
+		Name     string
+		Children []*node
+		Parent   *node
+		tags     map[string]int
+	}
+	root := &node{Name: "root", tags: map[string]int{"b": 2, "a": 1}}
+	child := &node{Name: "child", Parent: root}
+	root.Children = []*node{child}
+
+	cases := []struct {
+		depth int
+		text  string
+		json  string
+	}{
+		{0, `…`, `"…"`},
+		{1, `&{Name:root Children:… Parent:<nil> tags:…}`, `{"Name":"root","Children":"…","Parent":null}`},
+		{2, `&{Name:root Children:[…] Parent:<nil> tags:map[a:1 b:2]}`, `{"Name":"root","Children":["…"],"Parent":null}`},
+		{10, `&{Name:root Children:[&{Name:child Children:[] Parent:<cycle> tags:map[]}] Parent:<nil> tags:map[a:1 b:2]}`,
+			`{"Name":"root","Children":[{"Name":"child","Children":null,"Parent":"<cycle>"}],"Parent":null}`},
+	}
+	for _, c := range cases {
+		p := Pretty(root, c.depth)
+		if s := p.(fmt.Stringer).String(); s != c.text {
+			t.Errorf("depth %v, expect %v, actual %v", c.depth, c.text, s)
+		}
+		if data, err := p.(json.Marshaler).MarshalJSON(); err != nil || string(data) != c.json {
+			t.Errorf("depth %v, expect %v, actual %s, err %v", c.depth, c.json, data, err)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The log entry in protobuf, for binary log transport, which is equivalent to:
+//		message Entry {
+//			string level = 1;
+//			int64 ts = 2; // Unix time in nanoseconds.
+//			int32 pid = 3;
+//			int64 cid = 4; // Zero if no cid.
+//			string msg = 5; // The message with text fields, such as "message key=value".
+//		}
+// Each entry is written as a frame, the length in varint then the message,
+// which is the same as Java writeDelimitedTo and Go protodelim.
+type ProtoEntry struct {
+	Level string
+	Ts    int64
+	Pid   int32
+	Cid   int64
+	Msg   string
+}
+
+// Marshal the entry to protobuf message, without length.
+func (v *ProtoEntry) Marshal() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, v.Level)
+	b = appendProtoVarint(b, 2, uint64(v.Ts))
+	b = appendProtoVarint(b, 3, uint64(v.Pid))
+	b = appendProtoVarint(b, 4, uint64(v.Cid))
+	b = appendProtoString(b, 5, v.Msg)
+	return b
+}
+
+// Unmarshal the entry from protobuf message, without length.
+// @remark The unknown fields are ignored.
+func (v *ProtoEntry) Unmarshal(b []byte) error {
+	*v = ProtoEntry{}
+
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errors.New("proto: invalid tag")
+		}
+		b = b[n:]
+
+		num, typ := tag>>3, tag&7
+		switch typ {
+		case 0:
+			x, n := binary.Uvarint(b)
+			if n <= 0 {
+				return fmt.Errorf("proto: invalid varint of field %v", num)
+			}
+			b = b[n:]
+
+			switch num {
+			case 2:
+				v.Ts = int64(x)
+			case 3:
+				v.Pid = int32(x)
+			case 4:
+				v.Cid = int64(x)
+			}
+		case 2:
+			x, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < x {
+				return fmt.Errorf("proto: invalid bytes of field %v", num)
+			}
+			s := string(b[n : n+int(x)])
+			b = b[n+int(x):]
+
+			switch num {
+			case 1:
+				v.Level = s
+			case 5:
+				v.Msg = s
+			}
+		case 1, 5:
+			size := 8
+			if typ == 5 {
+				size = 4
+			}
+			if len(b) < size {
+				return fmt.Errorf("proto: invalid fixed of field %v", num)
+			}
+			b = b[size:]
+		default:
+			return fmt.Errorf("proto: unsupported wire type %v of field %v", typ, num)
+		}
+	}
+
+	return nil
+}
+
+func appendProtoVarint(b []byte, num int, x uint64) []byte {
+	if x == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(num)<<3)
+	return binary.AppendUvarint(b, x)
+}
+
+func appendProtoString(b []byte, num int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(num)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// The max size of frame to decode, to avoid huge allocation for corrupted stream.
+const maxProtoFrame = 16 * 1024 * 1024
+
+// The decoder to read the frames written in FormatProtobuf, for example:
+//		d := logger.NewProtoDecoder(r)
+//		for {
+//			e, err := d.Decode()
+//			if err != nil {
+//				break // The err is io.EOF when stream ends.
+//			}
+//			fmt.Println(e.Level, e.Msg)
+//		}
+type ProtoDecoder struct {
+	r *bufio.Reader
+}
+
+func NewProtoDecoder(r io.Reader) *ProtoDecoder {
+	return &ProtoDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode the next frame, return io.EOF when no more frames,
+// or io.ErrUnexpectedEOF if the last frame is truncated.
+func (v *ProtoDecoder) Decode() (*ProtoEntry, error) {
+	n, err := binary.ReadUvarint(v.r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxProtoFrame {
+		return nil, fmt.Errorf("proto: frame %v exceed %v", n, maxProtoFrame)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(v.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	e := &ProtoEntry{}
+	if err := e.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Encode the entry as a protobuf frame, see ProtoEntry.
+func (v *loggerPlus) encodeProto(e *entry) []byte {
+	pe := &ProtoEntry{
+		Level: v.name(),
+		Ts:    e.time.UnixNano(),
+		Pid:   int32(os.Getpid()),
+		Msg:   e.message,
+	}
+	if cid, ok := cidOf(e.ctx); ok {
+		pe.Cid = int64(cid)
+	}
+	if len(e.fields) > 0 {
+		pe.Msg = e.textMessage()
+	}
+
+	b := pe.Marshal()
+	return append(binary.AppendUvarint(nil, uint64(len(b))), b...)
+}
@@ -0,0 +1,48 @@
+// The protolog package renders proto.Message readable in logs, which is separated
+// from logger to keep the protobuf dependency optional.
+//		protolog.Install()
+//		logger.T(ctx, "got request", req)
+// Then the text log has the compact prototext of req, rather than the noisy %v of
+// the generated struct, and the JSON log has the protojson of fields.
+package protolog
+
+import (
+	"github.com/cheenwe/learn-go/logger"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// Install the formatters of proto.Message to logger.
+// @remark It replaces the formatters of logger.SetValueFormatter.
+func Install() {
+	logger.SetValueFormatter(Text, JSON)
+}
+
+// The compact prototext of v, false if it's not proto.Message.
+func Text(v interface{}) (string, bool) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return "", false
+	}
+
+	b, err := prototext.MarshalOptions{}.Marshal(m)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// The protojson of v, false if it's not proto.Message.
+func JSON(v interface{}) ([]byte, bool) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, false
+	}
+
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// The quota of bytes written per day.
+type dailyQuota struct {
+	limit int64
+	// The bytes written in day, such as 20060102, and whether warned the quota is exceeded.
+	used    int64
+	day     int64
+	tripped uint32
+}
+
+// Set the quota of bytes written to the underlayer io per day, for cost control, for example:
+//		logger.SetDailyQuota(10 << 30)
+// Once the quota is exceeded, all logs except Error are dropped until the next day,
+// and a warning is written when the quota trips, for example:
+//		[warn] 2006/01/02 15:04:05.000000 [pid] daily quota exceeded quota=10737418240
+// The day is in the timezone of SetTimeZone. Set to 0 to remove the quota, which is the default.
+// @remark The dropped logs are also counted as dropped of SetHeartbeat.
+// @remark The sinks and tee are not counted, only the underlayer io of Switch.
+func SetDailyQuota(bytes int64) {
+	updateOptions(WithDailyQuota(bytes))
+}
+
+// The day of t in loc, such as 20060102.
+func dayOf(t time.Time, loc *time.Location) int64 {
+	if loc != nil {
+		t = t.In(loc)
+	}
+	y, m, d := t.Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// Reset the quota if t is in a new day.
+func (v *dailyQuota) rotate(t time.Time, loc *time.Location) {
+	day := dayOf(t, loc)
+	if old := atomic.LoadInt64(&v.day); old != day && atomic.CompareAndSwapInt64(&v.day, old, day) {
+		atomic.StoreInt64(&v.used, 0)
+		atomic.StoreUint32(&v.tripped, 0)
+	}
+}
+
+// Whether allow a log at t, and whether to warn if not allowed, which is only once a day.
+func (v *dailyQuota) allow(t time.Time, loc *time.Location) (ok, warn bool) {
+	v.rotate(t, loc)
+	if atomic.LoadInt64(&v.used) < v.limit {
+		return true, false
+	}
+
+	atomic.AddUint64(&stats.dropped, 1)
+	return false, atomic.CompareAndSwapUint32(&v.tripped, 0, 1)
+}
+
+// Count the bytes written.
+func (v *dailyQuota) add(n int) {
+	atomic.AddInt64(&v.used, int64(n))
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The token bucket to limit the rate of logs.
+type rateLimiter struct {
+	lock sync.Mutex
+	// The max tokens, and the tokens added per second.
+	burst, rate float64
+	// The available tokens, updated at last.
+	tokens float64
+	last   time.Time
+	// The logs dropped since last allowed one.
+	dropped uint64
+}
+
+// Set the rate limit of level, allow up to burst logs immediately, then perSecond logs,
+// and drop the rest, for example, to avoid flooding by errors of a broken dependency:
+//		logger.SetRateLimit(logger.LevelError, 100, 10)
+// The next allowed log after drops is preceded by a summary, for example:
+//		[error] 2006/01/02 15:04:05.000000 [pid] rate limit dropped=37
+// Set burst or perSecond to 0 to remove the limit of level, which is the default.
+// @remark The disabled logs are discarded before limit, so never consume tokens.
+// @remark The dropped logs are also counted as dropped of SetHeartbeat.
+func SetRateLimit(level Level, burst int, perSecond float64) {
+	updateOptions(WithRateLimit(level, burst, perSecond))
+}
+
+// Take a token at now, return whether allowed, and the dropped logs to summarize if allowed.
+func (v *rateLimiter) allow(now time.Time) (bool, uint64) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if elapsed := now.Sub(v.last).Seconds(); elapsed > 0 {
+		v.tokens += elapsed * v.rate
+		if v.tokens > v.burst {
+			v.tokens = v.burst
+		}
+	}
+	v.last = now
+
+	if v.tokens < 1 {
+		v.dropped++
+		atomic.AddUint64(&stats.dropped, 1)
+		return false, 0
+	}
+
+	v.tokens--
+	dropped := v.dropped
+	v.dropped = 0
+	return true, dropped
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Recover the panic and log it at Error level with the stack, for example, in goroutine:
+//		go func() {
+//			defer logger.RecoverAndLog(ctx, false)
+//			......
+//		}()
+// Then the log is:
+//		[error] 2006/01/02 15:04:05.000000 [pid][cid] panic: boom stack:
+//		    goroutine 7 [running]:
+//		    ......
+// If all, the stacks of all goroutines are also logged as the goroutines field, for the
+// postmortem of deadlock or leak. In JSON, the stack and goroutines fields are arrays of lines.
+// @remark It must be called by defer directly, or the panic is not recovered.
+// @remark The dump is one log, so it's never interleaved with the concurrent logs.
+func RecoverAndLog(ctx Context, all bool) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	fields := []field{{"stack", stackLines(false)}}
+	if all {
+		fields = append(fields, field{"goroutines", stackLines(true)})
+	}
+	printFields(Error, ctx, fmt.Sprintf("panic: %v", r), fields...)
+}
+
+// The stack of current goroutine, or all goroutines, as lines.
+func stackLines(all bool) lines {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, all)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	return strings.Split(strings.TrimRight(string(buf), "\n"), "\n")
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Open the log file at path and switch to it, then reopen it when got sig,
+// which is compatible with logrotate, for example:
+//		if err := logger.InstallReopen(syscall.SIGHUP, "/var/log/app.log"); err != nil {
+//			return err
+//		}
+//		defer logger.Close()
+// The logrotate renames the file and sends SIGHUP, then the logger reopens path,
+// which creates a new file, and closes the renamed one.
+// @remark The file is swapped atomically under lock, so no log is lost or
+// 	written to the closed file. Close or next Switch stops the signal and closes the file.
+// @remark It also rotates on demand, see Rotate.
+func InstallReopen(sig os.Signal, path string) error {
+	w, err := newReopenFile(path, sig)
+	if err != nil {
+		return err
+	}
+
+	switchOwned(w)
+	return nil
+}
+
+// Open the log file at path, which reopens when got sig, or only by Rotate if sig is nil.
+func newReopenFile(path string, sig os.Signal) (*reopenFile, error) {
+	f, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &reopenFile{path: path, f: f, done: make(chan struct{})}
+	if sig != nil {
+		w.signals = make(chan os.Signal, 1)
+		signal.Notify(w.signals, sig)
+		go w.serve()
+	}
+	return w, nil
+}
+
+func openLogFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+// The log file which reopens when got signal.
+type reopenFile struct {
+	path string
+	// Nil if not reopen by signal.
+	signals chan os.Signal
+	done    chan struct{}
+	once    sync.Once
+
+	lock sync.Mutex
+	f    *os.File
+}
+
+func (v *reopenFile) serve() {
+	for {
+		select {
+		case <-v.done:
+			return
+		case <-v.signals:
+			if err := v.reopen(); err != nil {
+				handleError(loadOptions(), err)
+			}
+		}
+	}
+}
+
+// Open a new file then swap, keep the old file if failed.
+func (v *reopenFile) reopen() error {
+	f, err := openLogFile(v.path)
+	if err != nil {
+		return err
+	}
+
+	// Close the JSON array in the old file, then open it in the new one.
+	outputLock.Lock()
+	closeJSONArray(v)
+	v.lock.Lock()
+	old := v.f
+	v.f = f
+	v.lock.Unlock()
+	outputLock.Unlock()
+
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+func (v *reopenFile) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.f == nil {
+		return 0, os.ErrClosed
+	}
+	return v.f.Write(p)
+}
+
+func (v *reopenFile) Close() error {
+	v.once.Do(func() {
+		if v.signals != nil {
+			signal.Stop(v.signals)
+		}
+		close(v.done)
+	})
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.f == nil {
+		return nil
+	}
+	err := v.f.Close()
+	v.f = nil
+	return err
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// The last lines of logs, protected by outputLock.
+var ring struct {
+	lines []ringLine
+	// The position of oldest line when full.
+	head int
+}
+
+// The line in ring, with its level and tags.
+type ringLine struct {
+	level Level
+	tags  []string
+	line  []byte
+}
+
+// Set the size of ring to keep the last lines of logs in memory, default to 0 to disable,
+// for example, to show the last logs when crash, see DumpRing and DumpRingOnPanic:
+//		logger.SetRingSize(1000)
+//		defer logger.DumpRingOnPanic()
+// The lines are always text without color, even in JSON format.
+// @remark The kept lines are discarded when the size is changed.
+func SetRingSize(size int) {
+	updateOptions(WithRingSize(size))
+
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	ring.lines, ring.head = nil, 0
+}
+
+// Keep the line in ring, drop the oldest if full.
+// @remark The outputLock must be held.
+func keepRing(size int, level Level, tags []string, line []byte) {
+	if len(ring.lines) < size {
+		ring.lines = append(ring.lines, ringLine{level, tags, line})
+		return
+	}
+	ring.lines[ring.head] = ringLine{level, tags, line}
+	ring.head = (ring.head + 1) % len(ring.lines)
+}
+
+// Write the lines at or above minLevel in ring to w from oldest to newest, and clear the
+// ring if clear, for example, in an admin endpoint to poll the recent errors:
+//		http.HandleFunc("/errors", func(w http.ResponseWriter, r *http.Request) {
+//			logger.DumpRing(w, logger.LevelError, true)
+//		})
+// Then each poll only shows the errors since last poll, while the lines below minLevel
+// are also cleared. Use LevelInfo and false to dump all lines and keep them.
+func DumpRing(w io.Writer, minLevel Level, clear bool) error {
+	outputLock.Lock()
+	lines := append(append([]ringLine(nil), ring.lines[ring.head:]...), ring.lines[:ring.head]...)
+	if clear {
+		ring.lines, ring.head = nil, 0
+	}
+	outputLock.Unlock()
+
+	for _, line := range lines {
+		if line.level < minLevel {
+			continue
+		}
+		if _, err := w.Write(line.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dump the ring to stderr when panic, then panic again, which must be deferred directly:
+//		defer logger.DumpRingOnPanic()
+// @remark It does nothing if no panic.
+func DumpRingOnPanic() {
+	if r := recover(); r != nil {
+		DumpRing(os.Stderr, LevelInfo, false)
+		panic(r)
+	}
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"os"
+	"time"
+)
+
+// The writer which rotates on demand, such as the file of InstallReopen,
+// or lumberjack.Logger.
+type rotater interface {
+	Rotate() error
+}
+
+// Rotate the writers now, regardless of their thresholds, for the test harnesses or to
+// coordinate with the external backups, for example:
+//		logger.InstallReopen(syscall.SIGHUP, "/var/log/app.log")
+//		......
+//		if err := logger.Rotate(); err != nil {
+//			return err
+//		}
+// The underlayer io and the sinks which have Rotate() error are rotated, such as the file
+// of InstallReopen and SwitchPerLevelFiles, which is renamed with time suffix then reopened, the others are ignored,
+// so it returns nil if no rotating writer. The NewAsyncWriter is flushed before rotating
+// its writer, so the queued logs are in the current file, and each log is in one file.
+// @remark It returns the first error, and the writers after it are still rotated.
+func Rotate() (err error) {
+	for _, w := range currentWriters(loadOptions()) {
+		if a, ok := w.(*asyncWriter); ok {
+			a.flush()
+			w = a.w
+		}
+		if r, ok := w.(rotater); ok {
+			if e := r.Rotate(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return
+}
+
+// The path of rotated file, such as app.log.20060102-150405.000000.
+func rotatedPath(path string, t time.Time) string {
+	return path + "." + t.Format("20060102-150405.000000")
+}
+
+// Rename the file with time suffix, then reopen path, see Rotate.
+// @remark The open file can't be renamed on Windows.
+func (v *reopenFile) Rotate() error {
+	v.lock.Lock()
+	closed := v.f == nil
+	v.lock.Unlock()
+	if closed {
+		return os.ErrClosed
+	}
+
+	if err := os.Rename(v.path, rotatedPath(v.path, time.Now())); err != nil {
+		return err
+	}
+	return v.reopen()
+}
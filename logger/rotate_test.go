@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRotateReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	if err := InstallReopen(os.Interrupt, path); err != nil {
+		t.Fatal(err)
+	}
+	defer Switch(os.Stdout)
+
+	T(nil, "before")
+	if err := Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	T(nil, "after")
+	Close()
+
+	rotated, _ := filepath.Glob(path + ".*")
+	if len(rotated) != 1 {
+		t.Fatalf("expect 1 rotated file, actual %v", rotated)
+	}
+	if b, _ := ioutil.ReadFile(rotated[0]); !strings.HasSuffix(string(b), "] before\n") {
+		t.Errorf("unexpected rotated file %q", b)
+	}
+	if b, _ := ioutil.ReadFile(path); !strings.HasSuffix(string(b), "] after\n") || strings.Contains(string(b), "before") {
+		t.Errorf("unexpected current file %q", b)
+	}
+}
+
+func TestRotatePerLevelFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := SwitchPerLevelFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer Switch(os.Stdout)
+
+	W(nil, "before")
+	if err := Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	W(nil, "after")
+	owned := ownedIos
+	Switch(ioutil.Discard)
+
+	// The files are closed by next Switch.
+	for _, w := range owned {
+		if _, err := w.(*reopenFile).Write([]byte("x")); err != os.ErrClosed {
+			t.Errorf("expect closed, actual %v", err)
+		}
+	}
+
+	path := filepath.Join(dir, "warn.log")
+	if rotated, _ := filepath.Glob(path + ".*"); len(rotated) != 1 {
+		t.Fatalf("expect 1 rotated file, actual %v", rotated)
+	}
+	if b, _ := ioutil.ReadFile(path); !strings.HasSuffix(string(b), "] after\n") || strings.Contains(string(b), "before") {
+		t.Errorf("unexpected current file %q", b)
+	}
+}
+
+// The writer which rotates to a new file in memory.
+type testRotater struct {
+	lock  sync.Mutex
+	files []string
+}
+
+func (v *testRotater) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.files[len(v.files)-1] += string(p)
+	return len(p), nil
+}
+
+func (v *testRotater) Rotate() error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.files = append(v.files, "")
+	return nil
+}
+
+func TestRotateAsync(t *testing.T) {
+	r := &testRotater{files: []string{""}}
+	w := NewAsyncWriter(r, 1024)
+	Switch(w)
+	defer Switch(os.Stdout)
+
+	for i := 0; i < 100; i++ {
+		T(nil, "before")
+	}
+	if err := Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	T(nil, "after")
+	w.Close()
+
+	if len(r.files) != 2 || strings.Count(r.files[0], "before") != 100 || strings.Count(r.files[1], "after") != 1 ||
+		strings.Contains(r.files[1], "before") {
+		t.Errorf("unexpected files %q", r.files)
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+// Trace level log of untrusted data, which is never used as format, for example:
+//		logger.SafeF(ctx, r.URL.Path, "from", r.RemoteAddr)
+// Then the data is logged verbatim, with args appended by spaces, so it's safe even
+// the data has verbs such as %s, which breaks logger.Tf(ctx, r.URL.Path).
+func SafeF(ctx Context, data string, a ...interface{}) {
+	Trace.Println(ctx, append([]interface{}{data}, a...)...)
+}
+
+// Set whether check the format of printf, default to false, which warns when the format
+// has no verbs but args present, that's likely the data is misused as format:
+//		logger.Tf(ctx, name, value)
+// Then it warns "logger: format has no verbs but args present format=name args=1".
+// @remark It's for development, for the format is parsed for each log.
+func SetCheckFormat(check bool) {
+	updateOptions(WithCheckFormat(check))
+}
+
+// The number of verbs in format, the %% is not a verb.
+func countVerbs(format string) int {
+	verbs := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			i++
+			continue
+		}
+		verbs++
+	}
+	return verbs
+}
+
+// Warn if the format of entry has no verbs but args present.
+func checkFormat(e *entry) {
+	if e.println || e.format == "" || len(e.args) == 0 || countVerbs(e.format) > 0 {
+		return
+	}
+	if v, ok := Warn.(*loggerPlus); ok {
+		v.output(&entry{
+			ctx: e.ctx, message: "logger: format has no verbs but args present",
+			fields: []field{{"format", e.format}, {"args", len(e.args)}}, uncounted: true,
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"context"
+	"math/rand"
+)
+
+// Set the rate of requests to write the Info and Trace logs, default to 1 to write all,
+// for example, to write the detail logs of 10% requests:
+//		logger.SetTraceSampling(0.1)
+// The decision is made once for each request, so its logs are all written or not:
+//		ctx = logger.ContextWithSamplingDecision(ctx)
+//		logger.T(ctx, "sampled or not, same for all logs of ctx")
+// For the context with cid, the decision is by cid, so it's stable without caching.
+// The Warn and Error logs are always written, and the context without decision or cid
+// is always sampled.
+func SetTraceSampling(rate float64) {
+	updateOptions(WithTraceSampling(rate))
+}
+
+// The key of sampling decision in context.
+type samplingKey struct{}
+
+// Make the sampling decision for the request of ctx, and cache it in the returned context,
+// which is reused by all logs of it, see SetTraceSampling.
+// @remark The decision is never changed once made, even the rate is changed.
+// @remark The cid of ctx is kept, see PushPrefix.
+func ContextWithSamplingDecision(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(samplingKey{}).(bool); ok {
+		return ctx
+	}
+	// The value of context.Context is always context.Context.
+	return withValue(ctx, samplingKey{}, rand.Float64() < loadOptions().sampleRate).(context.Context)
+}
+
+// Whether the Info and Trace logs of ctx are sampled.
+func (v *options) sampled(ctx Context) bool {
+	if v.sampleRate >= 1 {
+		return true
+	}
+	if c, ok := ctx.(context.Context); ok {
+		if sampled, ok := c.Value(samplingKey{}).(bool); ok {
+			return sampled
+		}
+	}
+	if cid, ok := cidOf(ctx); ok {
+		// Spread the cid by Knuth's multiplicative hash, then compare to rate.
+		return float64(uint32(cid)*2654435761)/(1<<32) < v.sampleRate
+	}
+	return true
+}
@@ -0,0 +1,21 @@
+package logger
+
+import "sync/atomic"
+
+// The sequence of the last written log, see SetShowSequence.
+var sequence uint64
+
+// Set whether show the sequence number of log in prefix, such as [pid][cid][#42], default
+// to false. The number increases by one for each log of process in the order of written,
+// so the consumers detect the dropped or reordered logs by the gaps, for example, of
+// NewAsyncWriter or UnixSocketWriter. In JSON, it's the seq field.
+// @remark The number is shared by the underlayer io and the sinks, so the logs filtered
+// 	by level of sink also make gaps.
+func SetShowSequence(show bool) {
+	updateOptions(WithShowSequence(show))
+}
+
+// The next sequence number.
+func nextSequence() uint64 {
+	return atomic.AddUint64(&sequence, 1)
+}
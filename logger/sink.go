@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// The extra sink to write logs, besides the underlayer io.
+type sink struct {
+	w      io.Writer
+	format Format
+	level  Level
+	// The layout and timezone of time, empty and nil to follow the logger.
+	timeFormat string
+	timeZone   *time.Location
+}
+
+// The option of sink for AddSink.
+type SinkOption func(s *sink)
+
+// The option to render the time of sink in layout, such as time.RFC3339, for both text and
+// JSON log, so each sink has its own time format, see SetTimeFormat.
+func SinkTimeFormat(layout string) SinkOption {
+	return func(s *sink) {
+		s.timeFormat = layout
+	}
+}
+
+// The option to render the time of sink in timezone, such as time.UTC, see SetTimeZone.
+func SinkTimeZone(loc *time.Location) SinkOption {
+	return func(s *sink) {
+		s.timeZone = loc
+	}
+}
+
+// Create the sink, apply the options.
+func newSink(w io.Writer, format Format, minLevel Level, opts []SinkOption) *sink {
+	s := &sink{w: w, format: format, level: minLevel}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// The options of logger to encode for sink, which is o if the sink follows the logger,
+// otherwise a copy with the time of sink.
+func (v *sink) optionsOf(o *options) *options {
+	if v.timeFormat == "" && v.timeZone == nil {
+		return o
+	}
+
+	so := *o
+	if v.timeFormat != "" {
+		so.timeFormat, so.jsonTimeFormat, so.timeMode = v.timeFormat, v.timeFormat, ModeWallClock
+	}
+	if v.timeZone != nil {
+		so.timeZone = v.timeZone
+	}
+	return &so
+}
+
+// Add a sink to write logs in format, besides the underlayer io of Switch, and only
+// the logs at or above minLevel are written to it, for example, write all logs
+// to file while only warnings to console:
+//		logger.Switch(f)
+//		logger.AddSink(os.Stdout, logger.FormatText, logger.LevelWarn)
+// The level of SetLevel is still the floor for all sinks.
+// @remark The sink is removed and closed if it's an io.Closer by Close, except stdout and stderr.
+// The time of sink follows the logger by default, or set by options, for example, short
+// local time for console while RFC3339 in UTC for file:
+//		logger.AddSink(f, logger.FormatJSON, logger.LevelTrace,
+//			logger.SinkTimeFormat(time.RFC3339), logger.SinkTimeZone(time.UTC))
+// @remark The sink is colorized only if it's a terminal, see SetColorMode.
+func AddSink(w io.Writer, format Format, minLevel Level, opts ...SinkOption) {
+	s := newSink(w, format, minLevel, opts)
+	updateOptions(func(o *options) {
+		o.sinks = append(append([]*sink(nil), o.sinks...), s)
+	})
+}
+
+// Remove the sink, but not close it.
+func removeSink(s *sink) {
+	updateOptions(func(o *options) {
+		var sinks []*sink
+		for _, v := range o.sinks {
+			if v != s {
+				sinks = append(sinks, v)
+			}
+		}
+		o.sinks = sinks
+	})
+}
+
+// Remove all sinks and close them except stdout and stderr, return the first error.
+func removeSinks() (err error) {
+	var sinks []*sink
+	updateOptions(func(o *options) {
+		sinks, o.sinks = o.sinks, nil
+	})
+
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	for _, s := range sinks {
+		if s.w == os.Stdout || s.w == os.Stderr {
+			continue
+		}
+		if c, ok := s.w.(io.Closer); ok {
+			if r := c.Close(); r != nil && err == nil {
+				err = r
+			}
+		}
+	}
+	return
+}
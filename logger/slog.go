@@ -0,0 +1,128 @@
+//go:build go1.21
+// +build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// The slog.Handler which writes records by this logger.
+type slogHandler struct {
+	// The fields from WithAttrs, nested by groups.
+	fields []field
+	// The groups from WithGroup, the attrs of record are put into the last one.
+	groups []string
+}
+
+// Create a slog.Handler which writes records by this logger, for example:
+//		slog.SetDefault(slog.New(logger.NewSlogHandler()))
+// The slog levels are mapped to logger levels as:
+//		slog.LevelDebug to LevelInfo
+//		slog.LevelInfo to LevelTrace
+//		slog.LevelWarn to LevelWarn
+//		slog.LevelError to LevelError
+// @remark The context of record is passed as the Context of logger if it has cid.
+func NewSlogHandler() slog.Handler {
+	return &slogHandler{}
+}
+
+func (v *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slogLevel(level) >= loadOptions().levelOf(ctx)
+}
+
+func (v *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var attrs []field
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendSlogAttr(attrs, a)
+		return true
+	})
+
+	fields := v.fields
+	if len(attrs) > 0 {
+		fields = appendGroupFields(fields, v.groups, attrs)
+	}
+
+	// Use nil for context without cid, to keep the pid prefix.
+	var c Context
+	if _, ok := ctx.(cidContext); ok {
+		c = ctx
+	}
+	printEntry(levelLogger(slogLevel(r.Level)), &entry{ctx: c, message: r.Message, fields: fields, pc: r.PC})
+	return nil
+}
+
+func (v *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []field
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, a)
+	}
+	if len(fields) == 0 {
+		return v
+	}
+	return &slogHandler{fields: appendGroupFields(v.fields, v.groups, fields), groups: v.groups}
+}
+
+func (v *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return v
+	}
+	groups := append(append([]string(nil), v.groups...), name)
+	return &slogHandler{fields: v.fields, groups: groups}
+}
+
+func slogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelInfo
+	case level < slog.LevelWarn:
+		return LevelTrace
+	case level < slog.LevelError:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
+
+// Append the attr as field, the group attr is converted to group field,
+// and inlined if no key, and the empty attr is ignored.
+func appendSlogAttr(fields []field, a slog.Attr) []field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return append(fields, field{a.Key, a.Value.Any()})
+	}
+
+	var g group
+	for _, a := range a.Value.Group() {
+		g = appendSlogAttr(g, a)
+	}
+	if len(g) == 0 {
+		return fields
+	}
+	if a.Key == "" {
+		return append(fields, g...)
+	}
+	return append(fields, field{a.Key, g})
+}
+
+// Append the fields to the group of path, create the group if not exists,
+// and always copy the modified slices so the fields is never changed.
+func appendGroupFields(fields []field, path []string, added []field) []field {
+	fields = append([]field(nil), fields...)
+	if len(path) == 0 {
+		return append(fields, added...)
+	}
+
+	if n := len(fields); n > 0 && fields[n-1].key == path[0] {
+		if g, ok := fields[n-1].value.(group); ok {
+			fields[n-1].value = group(appendGroupFields(g, path[1:], added))
+			return fields
+		}
+	}
+	return append(fields, field{path[0], group(appendGroupFields(nil, path[1:], added))})
+}
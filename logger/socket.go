@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The max buffered lines during disconnection, the oldest is dropped when exceed.
+const maxSocketLines = 1000
+
+// The timeout to write a line, to avoid blocking logging by a stuck agent.
+const socketWriteTimeout = time.Second
+
+// The backoff to reconnect, doubled for each failure until the max.
+const minSocketBackoff, maxSocketBackoff = 100 * time.Millisecond, 5 * time.Second
+
+// Create the writer to stream logs to the Unix domain socket at path, where a local
+// agent listens, such as a sidecar log shipper, for example:
+//		logger.Switch(logger.UnixSocketWriter("/var/run/agent.sock"))
+//		defer logger.Close()
+// Each log is a line, which is framed by the newline. It connects in background, and
+// reconnects with backoff when the agent restarts, while buffers the lines until
+// reconnected, and the oldest is dropped if exceed 1000 lines.
+// @remark The dropped lines are counted as dropped of SetHeartbeat.
+// @remark Close stops reconnecting and closes the connection.
+func UnixSocketWriter(path string) io.WriteCloser {
+	v := &socketWriter{path: path, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	v.notify()
+	go v.serve()
+	return v
+}
+
+// The writer to Unix domain socket, which reconnects in background.
+type socketWriter struct {
+	path string
+	// To wake up the goroutine to connect, and to stop it.
+	wake chan struct{}
+	done chan struct{}
+
+	lock   sync.Mutex
+	conn   net.Conn
+	closed bool
+	// The lines to write when reconnected.
+	pending [][]byte
+}
+
+func (v *socketWriter) notify() {
+	select {
+	case v.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (v *socketWriter) serve() {
+	backoff := minSocketBackoff
+	for {
+		select {
+		case <-v.done:
+			return
+		case <-v.wake:
+		}
+
+		for !v.connect() {
+			select {
+			case <-v.done:
+				return
+			case <-time.After(backoff):
+			}
+
+			if backoff *= 2; backoff > maxSocketBackoff {
+				backoff = maxSocketBackoff
+			}
+		}
+		backoff = minSocketBackoff
+	}
+}
+
+// Connect if not connected and write the pending lines, return false to retry.
+func (v *socketWriter) connect() bool {
+	v.lock.Lock()
+	connected := v.conn != nil || v.closed
+	v.lock.Unlock()
+	if connected {
+		return true
+	}
+
+	conn, err := net.Dial("unix", v.path)
+	if err != nil {
+		reportf("connect socket %v failed, err is %v", v.path, err)
+		return false
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.closed {
+		conn.Close()
+		return true
+	}
+
+	for len(v.pending) > 0 {
+		if err := v.writeLine(conn, v.pending[0]); err != nil {
+			conn.Close()
+			return false
+		}
+		v.pending = v.pending[1:]
+	}
+	v.pending = nil
+	v.conn = conn
+	return true
+}
+
+func (v *socketWriter) writeLine(conn net.Conn, p []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+	_, err := conn.Write(p)
+	return err
+}
+
+func (v *socketWriter) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.closed {
+		return 0, os.ErrClosed
+	}
+
+	if v.conn != nil {
+		if err := v.writeLine(v.conn, p); err == nil {
+			return len(p), nil
+		}
+		v.conn.Close()
+		v.conn = nil
+	}
+
+	if len(v.pending) >= maxSocketLines {
+		v.pending = v.pending[1:]
+		atomic.AddUint64(&stats.dropped, 1)
+		reportf("socket %v is disconnected and buffer is full, drop", v.path)
+	}
+	v.pending = append(v.pending, append([]byte(nil), p...))
+	v.notify()
+	return len(p), nil
+}
+
+func (v *socketWriter) Close() error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.closed {
+		return nil
+	}
+	v.closed = true
+	close(v.done)
+
+	v.pending = nil
+	if v.conn == nil {
+		return nil
+	}
+	err := v.conn.Close()
+	v.conn = nil
+	return err
+}
@@ -0,0 +1,47 @@
+package logger
+
+import "time"
+
+// Log the SQL query with its duration and error, at the level by the error and slowness,
+// for example, in the wrapper of database/sql:
+//		start := time.Now()
+//		rows, err := db.QueryContext(ctx, query, args...)
+//		logger.LogSQL(ctx, query, args, time.Since(start), err)
+// Then the log is:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] sql query="SELECT * FROM users WHERE id=?" duration_ms=1.5
+// The level is Error if err is not nil, Warn if slower than SetSQLSlowThreshold, otherwise Trace.
+// In JSON, the query, duration_ms, args and error are fields.
+// @remark The args are only logged if SetSQLArgs, for they may carry the PII.
+func LogSQL(ctx Context, query string, args []interface{}, dur time.Duration, err error) {
+	o := loadOptions()
+
+	fields := []field{
+		{"query", query},
+		{"duration_ms", float64(dur) / float64(time.Millisecond)},
+	}
+	if o.sqlArgs && len(args) > 0 {
+		fields = append(fields, field{"args", args})
+	}
+
+	l := Trace
+	if err != nil {
+		l = Error
+		fields = append(fields, field{"error", err})
+	} else if o.sqlSlowThreshold > 0 && dur >= o.sqlSlowThreshold {
+		l = Warn
+	}
+	printFields(l, ctx, "sql", fields...)
+}
+
+// Set whether log the args of LogSQL, default to false, for they may carry the PII
+// such as the name and phone of user.
+func SetSQLArgs(enabled bool) {
+	updateOptions(WithSQLArgs(enabled))
+}
+
+// Set the threshold of slow query for LogSQL, which is logged at Warn level,
+// default to 0 to disable, for example:
+//		logger.SetSQLSlowThreshold(200 * time.Millisecond)
+func SetSQLSlowThreshold(threshold time.Duration) {
+	updateOptions(WithSQLSlowThreshold(threshold))
+}
@@ -0,0 +1,21 @@
+package logger
+
+// The key of prefix stack in context.
+type prefixStackKey struct{}
+
+// Push s to the prefix stack of ctx, return the derived context to pass down, for example:
+//		ctx = logger.PushPrefix(ctx, "http")
+//		ctx = logger.PushPrefix(ctx, "auth")
+//		logger.T(ctx, "check token")
+// Then the log is "[pid][cid][http][auth] check token". In JSON, it's the prefix field.
+// For context.Context, the stack is stored as value, so the deadline and values are kept.
+// @remark The cid of ctx is kept, while other methods of ctx are not for non context.Context.
+func PushPrefix(ctx Context, s string) Context {
+	return withValue(ctx, prefixStackKey{}, prefixStackOf(ctx)+"["+s+"]")
+}
+
+// The prefix stack of ctx, such as [http][auth], empty if none.
+func prefixStackOf(ctx Context) string {
+	s, _ := valueOf(ctx, prefixStackKey{}).(string)
+	return s
+}
@@ -0,0 +1,57 @@
+package logger
+
+import "io"
+
+// The stream style of JSON log.
+type JSONStreamStyle int
+
+const (
+	// The newline-delimited JSON, one object per line, the default style.
+	StyleNDJSON JSONStreamStyle = iota
+	// The single JSON array, such as [{...},{...}].
+	StyleArray
+)
+
+// The writers which have opened the JSON array, protected by outputLock.
+var jsonArrays []io.Writer
+
+// Set the stream style of JSON log, StyleNDJSON or StyleArray.
+// For StyleArray, the opening bracket is written before the first log, the comma before
+// the others, and the closing bracket by Close, so the output is a valid JSON array:
+//		[{"ts":"...","msg":"first"}
+//		,{"ts":"...","msg":"second"}
+//		]
+// For InstallReopen, the array is closed in the old file and opened in the new one.
+// @remark It only works for the underlayer io, the sinks and tee are always NDJSON.
+// @remark The array is not valid if the process crashes before Close, or the file is
+// 	appended by another process, so it's only for the consumers which can't read NDJSON.
+func SetJSONStreamStyle(style JSONStreamStyle) {
+	updateOptions(WithJSONStreamStyle(style))
+}
+
+// The byte before the JSON log to frame it as an element of array for w, [ for the first one.
+// @remark The outputLock must be held.
+func frameJSONArray(w io.Writer) byte {
+	for _, a := range jsonArrays {
+		if sameWriter(a, w) {
+			return ','
+		}
+	}
+
+	jsonArrays = append(jsonArrays, w)
+	return '['
+}
+
+// Close the JSON array of w, or all writers if w is nil.
+// @remark The outputLock must be held.
+func closeJSONArray(w io.Writer) {
+	var opened []io.Writer
+	for _, a := range jsonArrays {
+		if w == nil || sameWriter(a, w) {
+			a.Write([]byte("]\n"))
+		} else {
+			opened = append(opened, a)
+		}
+	}
+	jsonArrays = opened
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Set whether check the misuse of logger, default to false, for development builds to flush
+// out the concurrency bugs, for example, in TestMain:
+//		logger.SetStrictConcurrency(true)
+// It panics for the violations:
+//		Log after Close, by the loggers replaced by Close or created by it.
+//		Reuse a buffer which is freed, or free a buffer twice.
+// @remark Never enable it in production, the checks are extra synchronization, while
+// 	it's zero overhead when disabled.
+// @remark Set it before any log, or the buffers in use when set are reported as freed twice.
+func SetStrictConcurrency(strict bool) {
+	updateOptions(WithStrictConcurrency(strict))
+}
+
+// Report the violation of strict concurrency.
+func strictViolation(format string, a ...interface{}) {
+	panic(fmt.Sprintf("logger: strict concurrency: "+format, a...))
+}
+
+// The buffers in use, only tracked for strict concurrency.
+var strictBuffers struct {
+	lock  sync.Mutex
+	inUse map[*bytes.Buffer]bool
+}
+
+// Track the buffer b is got from or put back to pool.
+func strictBuffer(b *bytes.Buffer, get bool) {
+	strictBuffers.lock.Lock()
+	defer strictBuffers.lock.Unlock()
+
+	if strictBuffers.inUse == nil {
+		strictBuffers.inUse = make(map[*bytes.Buffer]bool)
+	}
+	if get && strictBuffers.inUse[b] {
+		strictViolation("buffer %p is in use", b)
+	}
+	if !get && !strictBuffers.inUse[b] {
+		strictViolation("buffer %p is freed twice", b)
+	}
+	if get {
+		strictBuffers.inUse[b] = true
+	} else {
+		delete(strictBuffers.inUse, b)
+	}
+}
+
+// Mark the logger as closed, for strict concurrency.
+func markClosed(l Logger) {
+	if v, ok := l.(*loggerPlus); ok {
+		atomic.StoreUint32(&v.closed, 1)
+	}
+}
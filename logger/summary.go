@@ -0,0 +1,69 @@
+package logger
+
+import "time"
+
+// The max contexts to track the counts for Summary, all are reset when exceed.
+const maxSummaryContexts = 10000
+
+// The counts of warns and errors of each cid, protected by outputLock.
+var summaryCounts map[int]*summaryCount
+
+type summaryCount struct {
+	warns, errors int
+}
+
+// Count the written log of level for ctx.
+// @remark The outputLock must be held.
+func countSummary(ctx Context, level Level) {
+	if level < LevelWarn {
+		return
+	}
+	cid, ok := cidOf(ctx)
+	if !ok {
+		return
+	}
+
+	if summaryCounts == nil || len(summaryCounts) >= maxSummaryContexts {
+		summaryCounts = make(map[int]*summaryCount)
+	}
+	c := summaryCounts[cid]
+	if c == nil {
+		c = &summaryCount{}
+		summaryCounts[cid] = c
+	}
+	if level == LevelWarn {
+		c.warns++
+	} else {
+		c.errors++
+	}
+}
+
+// Log a summary line at the end of request, with the fields in key-value pairs and the
+// counts of warns and errors of ctx, as a receipt of request, for example:
+//		defer logger.Summary(ctx, "path", r.URL.Path, "status", status)
+// Then the log is:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] summary path=/api status=200 warns=1 errors=0
+// The latency_ms is added if ctx has start, see ContextWithStart. The counts are matched by
+// cid and reset after summary, so it should be called once for each request.
+func Summary(ctx Context, kv ...interface{}) {
+	var c summaryCount
+	if cid, ok := cidOf(ctx); ok {
+		outputLock.Lock()
+		if p := summaryCounts[cid]; p != nil {
+			c = *p
+			delete(summaryCounts, cid)
+		}
+		outputLock.Unlock()
+	}
+
+	fields := append(kvFields(kv), field{"warns", c.warns}, field{"errors", c.errors})
+	if start, ok := valueOf(ctx, startKey{}).(time.Time); ok {
+		fields = append(fields, field{"latency_ms", float64(time.Since(start)) / float64(time.Millisecond)})
+	}
+	printFields(levelLogger(loadOptions().summaryLevel), ctx, "summary", fields...)
+}
+
+// Set the level of Summary, default to LevelTrace.
+func SetSummaryLevel(level Level) {
+	updateOptions(WithSummaryLevel(level))
+}
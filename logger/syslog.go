@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// The facility of syslog, which is routed by rsyslog rules, such as local0.* to a file.
+type Facility int
+
+const (
+	FacilityLocal0 Facility = 16 + iota
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// The severity of syslog for each level, index by Level.
+var syslogSeverities = [...]int{
+	7, // debug for LevelInfo
+	6, // info for LevelTrace
+	4, // warning for LevelWarn
+	3, // err for LevelError
+}
+
+// The priority of syslog, which is facility*8+severity.
+func syslogPriority(facility Facility, level Level) int {
+	if level < LevelInfo || level > LevelError {
+		level = LevelTrace
+	}
+	return int(facility)*8 + syslogSeverities[level]
+}
+
+// Create the writer to syslog at addr of network, such as "udp" and "localhost:514",
+// or "unixgram" and "/dev/log", for example:
+//		w, err := logger.NewSyslogWriter("unixgram", "/dev/log", logger.FacilityLocal3, "app")
+// Each log is a message in RFC3164, whose priority is by the facility and the level of log,
+// which is parsed from the label of text log or the level field of JSON log:
+//		Info to debug, Trace to info, Warn to warning, Error to err
+// The facility must be one of FacilityLocal0 to FacilityLocal7, or 0 for FacilityLocal0.
+// The tag is the name of program if empty.
+// @remark It reconnects once when failed to write, such as the syslog daemon restarts. If
+// 	failed to reconnect, the logs are dropped, and it reconnects by the next write with
+// 	backoff, see UnixSocketWriter.
+func NewSyslogWriter(network, addr string, facility Facility, tag string) (io.WriteCloser, error) {
+	if facility == 0 {
+		facility = FacilityLocal0
+	}
+	if facility < FacilityLocal0 || facility > FacilityLocal7 {
+		return nil, fmt.Errorf("invalid syslog facility %v, should be local0 to local7", int(facility))
+	}
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+
+	v := &syslogWriter{network: network, addr: addr, facility: facility, tag: tag}
+	if err := v.connect(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Create the syslog writer and switch to it, see NewSyslogWriter.
+// @remark Close or next Switch closes the writer.
+func SwitchSyslog(network, addr string, facility Facility, tag string) error {
+	w, err := NewSyslogWriter(network, addr, facility, tag)
+	if err != nil {
+		return err
+	}
+	switchOwned(w)
+	return nil
+}
+
+// The writer to syslog, one message for each line.
+type syslogWriter struct {
+	network, addr string
+	facility      Facility
+	tag           string
+
+	lock sync.Mutex
+	// Nil if disconnected or closed.
+	conn   net.Conn
+	closed bool
+	// The partial line, wait for the line ending.
+	pending []byte
+	// The backoff to reconnect when disconnected, and the time of next reconnect.
+	backoff time.Duration
+	retryAt time.Time
+}
+
+func (v *syslogWriter) connect() error {
+	conn, err := net.Dial(v.network, v.addr)
+	if err != nil {
+		return err
+	}
+	v.conn = conn
+	return nil
+}
+
+// Reconnect if the backoff is passed, which is doubled for each failure until the max.
+func (v *syslogWriter) reconnect() error {
+	now := time.Now()
+	if now.Before(v.retryAt) {
+		return fmt.Errorf("syslog %v %v disconnected, reconnect after %v", v.network, v.addr, v.retryAt.Sub(now))
+	}
+
+	if err := v.connect(); err != nil {
+		if v.backoff *= 2; v.backoff < minSocketBackoff {
+			v.backoff = minSocketBackoff
+		} else if v.backoff > maxSocketBackoff {
+			v.backoff = maxSocketBackoff
+		}
+		v.retryAt = now.Add(v.backoff)
+		return err
+	}
+	v.backoff, v.retryAt = 0, time.Time{}
+	return nil
+}
+
+func (v *syslogWriter) Write(p []byte) (int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.closed {
+		return 0, os.ErrClosed
+	}
+
+	var err error
+	v.pending = splitLines(v.pending, p, func(line []byte) {
+		if err == nil {
+			err = v.writeLine(line)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Write the line as a message, reconnect and retry once if failed.
+func (v *syslogWriter) writeLine(line []byte) error {
+	level := LevelTrace
+	if e := parseEntry(line); e.HasLevel {
+		level = e.Level
+	}
+	message := fmt.Sprintf("<%d>%s %s[%d]: %s", syslogPriority(v.facility, level),
+		time.Now().Format(time.Stamp), v.tag, os.Getpid(), line)
+
+	if v.conn != nil {
+		if _, err := io.WriteString(v.conn, message); err == nil {
+			return nil
+		}
+		v.conn.Close()
+		v.conn = nil
+	}
+
+	if err := v.reconnect(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(v.conn, message)
+	return err
+}
+
+func (v *syslogWriter) Close() error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.closed = true
+	if v.conn == nil {
+		return nil
+	}
+	err := v.conn.Close()
+	v.conn = nil
+	return err
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogPriority(t *testing.T) {
+	cases := []struct {
+		facility Facility
+		level    Level
+		expect   int
+	}{
+		{FacilityLocal0, LevelInfo, 135},
+		{FacilityLocal0, LevelTrace, 134},
+		{FacilityLocal0, LevelWarn, 132},
+		{FacilityLocal0, LevelError, 131},
+		{FacilityLocal7, LevelError, 187},
+		{FacilityLocal3, Level(100), 158},
+	}
+	for _, c := range cases {
+		if p := syslogPriority(c.facility, c.level); p != c.expect {
+			t.Errorf("facility %v level %v, expect %v, actual %v", c.facility, c.level, c.expect, p)
+		}
+	}
+
+	if _, err := NewSyslogWriter("udp", "127.0.0.1:514", Facility(1), ""); err == nil {
+		t.Error("expect error for invalid facility")
+	}
+}
+
+func TestSyslogReconnect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no unixgram")
+	}
+	dir, err := ioutil.TempDir("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log.sock")
+	listen := func() *net.UnixConn {
+		l, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return l
+	}
+
+	l := listen()
+	w, err := NewSyslogWriter("unixgram", path, FacilityLocal0, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// The daemon restarts, so the writer is disconnected.
+	l.Close()
+	os.Remove(path)
+	if _, err := w.Write([]byte("lost\n")); err == nil {
+		t.Fatal("expect error when daemon is down")
+	}
+	if _, err := w.Write([]byte("lost\n")); err == nil || !strings.Contains(err.Error(), "reconnect after") {
+		t.Fatalf("expect backoff, actual %v", err)
+	}
+
+	l = listen()
+	defer l.Close()
+	w.(*syslogWriter).retryAt = time.Time{}
+	if _, err := w.Write([]byte("back\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	b := make([]byte, 1024)
+	n, err := l.Read(b)
+	if err != nil || !strings.HasSuffix(string(b[:n]), ": back") {
+		t.Errorf("unexpected message %q, err is %v", b[:n], err)
+	}
+}
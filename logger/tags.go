@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+)
+
+// The key of tags in context.
+type tagsKey struct{}
+
+// Whether WithTags is ever called, to skip looking up tags of context if not.
+var tagsUsed uint32
+
+// Derive ctx with the tags, which are attached to all logs of the derived context, to slice
+// the recent logs by arbitrary categories, for example:
+//		ctx = logger.WithTags(ctx, "db", "slow")
+//		logger.T(ctx, "query users") // [trace] 2006/01/02 15:04:05.000000 [pid][cid] query users #db #slow
+// The tags are distinct from fields, which is an array in JSON log, such as "tags":["db","slow"],
+// and the lines in ring are filtered by tag, see DumpRingWithTag.
+// The tags of parent are kept, and the duplicated tags are ignored.
+// @remark The cid of ctx is kept, see PushPrefix.
+func WithTags(ctx Context, tags ...string) Context {
+	atomic.StoreUint32(&tagsUsed, 1)
+
+	parent := tagsOf(ctx)
+	merged := append(make([]string, 0, len(parent)+len(tags)), parent...)
+	for _, tag := range tags {
+		if tag != "" && !containsString(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return withValue(ctx, tagsKey{}, merged)
+}
+
+// The tags of ctx by WithTags, nil if none.
+func tagsOf(ctx Context) []string {
+	if atomic.LoadUint32(&tagsUsed) == 0 {
+		return nil
+	}
+	tags, _ := valueOf(ctx, tagsKey{}).([]string)
+	return tags
+}
+
+// Write the tags as #db #slow, with a leading space.
+func encodeTextTags(b *bytes.Buffer, tags []string) {
+	for _, tag := range tags {
+		b.WriteString(" #")
+		b.WriteString(tag)
+	}
+}
+
+// Write the lines with tag in ring to w from oldest to newest, for example, to show
+// the recent logs of database:
+//		logger.DumpRingWithTag(os.Stderr, "db")
+// The ring is kept, see DumpRing.
+func DumpRingWithTag(w io.Writer, tag string) error {
+	outputLock.Lock()
+	lines := append(append([]ringLine(nil), ring.lines[ring.head:]...), ring.lines[:ring.head]...)
+	outputLock.Unlock()
+
+	for _, line := range lines {
+		if !containsString(line.tags, tag) {
+			continue
+		}
+		if _, err := w.Write(line.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Whether the entry has the tag, for example, to filter the entries of channel sink:
+//		for e := range entries {
+//			if e.HasTag("db") {
+//				......
+//			}
+//		}
+// @remark The tags are only parsed for JSON log, see DecodeEntry.
+func (v Entry) HasTag(tag string) bool {
+	return containsString(v.Tags, tag)
+}
@@ -0,0 +1,61 @@
+package logger
+
+import "io"
+
+// The tee writers, key by cid, protected by outputLock.
+var tees map[int]*tee
+
+type tee struct {
+	w io.Writer
+}
+
+// Mirror the logs of ctx to w besides the underlayer io, for example, to
+// capture all logs of a problematic connection in a separated file:
+//		f, _ := os.Create(fmt.Sprintf("conn-%v.log", ctx.Cid()))
+//		stop := logger.TeeContextTo(ctx, f)
+//		defer stop()
+// The logs are matched by cid, so all contexts of the same cid are mirrored.
+// The returned stop removes the tee, then closes w if it's an io.Closer.
+// @remark Nothing is mirrored for context without cid, and stop only closes w.
+// @remark The mirrored logs never have color.
+func TeeContextTo(ctx Context, w io.Writer) (stop func() error) {
+	t := &tee{w: w}
+	cid, ok := cidOf(ctx)
+	if ok {
+		outputLock.Lock()
+		if tees == nil {
+			tees = make(map[int]*tee)
+		}
+		tees[cid] = t
+		outputLock.Unlock()
+	}
+
+	return func() error {
+		if ok {
+			outputLock.Lock()
+			if tees[cid] == t {
+				delete(tees, cid)
+			}
+			outputLock.Unlock()
+		}
+
+		if w, ok := w.(io.Closer); ok {
+			return w.Close()
+		}
+		return nil
+	}
+}
+
+// The tee writer of ctx, nil if not mirrored.
+// @remark The outputLock must be held.
+func teeWriter(ctx Context) io.Writer {
+	if len(tees) == 0 {
+		return nil
+	}
+	if cid, ok := cidOf(ctx); ok {
+		if t := tees[cid]; t != nil {
+			return t.w
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// The result of isTerminal, key by *os.File.
+var terminals sync.Map
+
+// Whether w is a terminal, by the character device of file, cached for each file.
+// @remark The writer which is not *os.File, such as pipe wrapper, is not terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	if r, ok := terminals.Load(f); ok {
+		return r.(bool)
+	}
+
+	var r bool
+	if info, err := f.Stat(); err == nil {
+		r = info.Mode()&os.ModeCharDevice != 0
+	}
+	terminals.Store(f, r)
+	return r
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestIsTerminal(t *testing.T) {
+	f, err := ioutil.TempFile("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if isTerminal(f) {
+		t.Errorf("regular file %v is terminal", f.Name())
+	}
+
+	pty, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pty, err is %v", err)
+	}
+	defer pty.Close()
+
+	if !isTerminal(pty) {
+		t.Errorf("pty is not terminal")
+	}
+
+	v := newLoggerPlus(pty, LevelError)
+	if c := v.color(loadOptions(), pty, FormatText); c != colorRed {
+		t.Errorf("no color for pty, actual %q", c)
+	}
+	if c := v.color(loadOptions(), f, FormatText); c != "" {
+		t.Errorf("color for file, actual %q", c)
+	}
+}
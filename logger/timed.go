@@ -0,0 +1,30 @@
+package logger
+
+import "time"
+
+// Log the elapsed time at level when the returned func runs, for example:
+//		defer logger.Timed(ctx, logger.LevelTrace, "handleRequest")()
+// Then the log is:
+//		[trace] 2006/01/02 15:04:05.000000 [pid][cid] handleRequest done duration_ms=1.5
+// In JSON, the duration_ms is a number. Use TimedStart to also log the start.
+func Timed(ctx Context, level Level, label string) func() {
+	return timed(ctx, level, label, false)
+}
+
+// Log the start at level, and the elapsed time when the returned func runs, see Timed.
+func TimedStart(ctx Context, level Level, label string) func() {
+	return timed(ctx, level, label, true)
+}
+
+func timed(ctx Context, level Level, label string, start bool) func() {
+	if start {
+		printFields(levelLogger(level), ctx, label+" start")
+	}
+
+	begin := time.Now()
+	return func() {
+		printFields(levelLogger(level), ctx, label+" done",
+			field{"duration_ms", float64(time.Since(begin)) / float64(time.Millisecond)},
+		)
+	}
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// The start time of process, for ModeUptime.
+var processStart time.Time
+
+func init() {
+	processStart = time.Now()
+}
+
+// The mode of time in text log.
+type TimeMode int
+
+const (
+	// The wall clock time, the default mode, see SetTimeFormat.
+	ModeWallClock TimeMode = iota
+	// The elapsed time since process start, like dmesg, for example:
+	//		[trace] [   12.345678] [pid] message
+	ModeUptime
+)
+
+// Set the mode of time in text log, ModeWallClock or ModeUptime, for example, to compare
+// the relative timing across runs:
+//		logger.SetTimeMode(logger.ModeUptime)
+// @remark The JSON log always uses the wall clock time.
+func SetTimeMode(mode TimeMode) {
+	updateOptions(WithTimeMode(mode))
+}
+
+// The uptime of entry, such as [   12.345678], in fixed width for uptime less than a day.
+func uptimeOf(e *entry) string {
+	d := e.time.Sub(processStart)
+	if d < 0 {
+		d = 0
+	}
+	return fmt.Sprintf("[%5d.%06d]", d/time.Second, d%time.Second/time.Microsecond)
+}
+
+// The layout of ISO8601 with milliseconds and timezone offset.
+const timeFormatISO8601 = "2006-01-02T15:04:05.000-07:00"
+
+// Set the layout of time in text log, such as time.RFC3339, default to empty to use
+// the log flags, which is 2006/01/02 15:04:05.000000. Set to empty to restore it.
+// @remark The JSON log always uses its own layout, with the timezone of SetTimeZone.
+func SetTimeFormat(layout string) {
+	updateOptions(WithTimeFormat(layout))
+}
+
+// Set the layout of time in text log to ISO8601 with timezone offset, which is parsed
+// natively by many log aggregators, for example:
+//		[trace] 2024-01-02T15:04:05.000-07:00 [pid] message
+func SetTimeFormatISO8601() {
+	SetTimeFormat(timeFormatISO8601)
+}
+
+// Set the timezone of time in log, such as time.UTC, default to nil to use the local
+// timezone, or UTC if the log flags has log.LUTC.
+func SetTimeZone(loc *time.Location) {
+	updateOptions(WithTimeZone(loc))
+}
+
+// The time of entry in timezone of options or log flags.
+func (v *loggerPlus) timeOf(o *options, e *entry) time.Time {
+	if o.timeZone != nil {
+		return e.time.In(o.timeZone)
+	}
+	if v.logger.Flags()&log.LUTC != 0 {
+		return e.time.UTC()
+	}
+	return e.time
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeFormatISO8601(t *testing.T) {
+	e := &entry{
+		time:    time.Date(2024, 1, 2, 22, 4, 5, 123456000, time.UTC),
+		message: "The log text.",
+	}
+	expect := fmt.Sprintf("[trace] 2024-01-02T15:04:05.123-07:00 [%v] The log text.", os.Getpid())
+
+	SetTimeFormatISO8601()
+	SetTimeZone(time.FixedZone("MST", -7*3600))
+	defer SetTimeFormat("")
+	defer SetTimeZone(nil)
+
+	var b bytes.Buffer
+	newLoggerPlus(ioutil.Discard, LevelTrace).encodeText(&b, loadOptions(), e, "", nil)
+	if s := b.String(); s != expect {
+		t.Errorf("expect %v, actual %v", expect, s)
+	}
+}
+
+func TestTimeModeUptime(t *testing.T) {
+	e := &entry{
+		time:    processStart.Add(12345678 * time.Microsecond),
+		message: "The log text.",
+	}
+	expect := fmt.Sprintf("[trace] [   12.345678] [%v] The log text.", os.Getpid())
+
+	SetTimeMode(ModeUptime)
+	defer SetTimeMode(ModeWallClock)
+
+	var b bytes.Buffer
+	newLoggerPlus(ioutil.Discard, LevelTrace).encodeText(&b, loadOptions(), e, "", nil)
+	if s := b.String(); s != expect {
+		t.Errorf("expect %v, actual %v", expect, s)
+	}
+}
+
+func TestAtTime(t *testing.T) {
+	var console bytes.Buffer
+	Switch(&console)
+	defer Switch(os.Stdout)
+
+	file := NewCaptureWriter()
+	AddSink(file, FormatJSON, LevelTrace)
+	defer removeSinks()
+
+	ts := time.Date(2024, 1, 2, 15, 4, 5, 123456000, time.Local)
+	Tf(nil, "got %v orders", 3, AtTime(ts))
+	T(nil, "zero", AtTime(time.Time{}))
+
+	lines := strings.Split(console.String(), "\n")
+	expect := fmt.Sprintf("[trace] 2024/01/02 15:04:05.123456 [%v] got 3 orders", os.Getpid())
+	if len(lines) != 3 || lines[0] != expect {
+		t.Errorf("expect %v, actual %q", expect, console.String())
+	}
+	if strings.Contains(lines[1], "2024/01/02") || !strings.HasSuffix(lines[1], "] zero") {
+		t.Errorf("unexpected log of zero time %q", lines[1])
+	}
+
+	entries := file.Get()
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, actual %v", len(entries))
+	}
+	if !entries[0].Time.Equal(ts) || entries[0].Message != "got 3 orders" {
+		t.Errorf("unexpected entry %+v", entries[0])
+	}
+	if entries[1].Time.Before(ts.AddDate(1, 0, 0)) {
+		t.Errorf("expect now for zero time, actual %v", entries[1].Time)
+	}
+}
+
+func TestSinkTimeFormat(t *testing.T) {
+	Switch(ioutil.Discard)
+	defer Switch(os.Stdout)
+
+	var console, file bytes.Buffer
+	AddSink(&console, FormatText, LevelTrace, SinkTimeFormat("15:04:05"), SinkTimeZone(time.FixedZone("MST", -7*3600)))
+	AddSink(&file, FormatJSON, LevelTrace, SinkTimeFormat(time.RFC3339), SinkTimeZone(time.UTC))
+	defer removeSinks()
+
+	T(nil, "The log text.", AtTime(time.Date(2024, 1, 2, 22, 4, 5, 0, time.UTC)))
+
+	expect := fmt.Sprintf("[trace] 15:04:05 [%v] The log text.\n", os.Getpid())
+	if s := console.String(); s != expect {
+		t.Errorf("expect %q, actual %q", expect, s)
+	}
+	expect = `{"ts":"2024-01-02T22:04:05Z",`
+	if s := file.String(); !strings.HasPrefix(s, expect) {
+		t.Errorf("expect prefix %q, actual %q", expect, s)
+	}
+}
@@ -0,0 +1,54 @@
+package logger
+
+// The logger of topic at level, which writes by the package logger of level.
+type topicLogger struct {
+	name  string
+	level Level
+}
+
+// Create the Trace logger of topic, which has the level of topic, for example:
+//		logger.SetTopicLevel("sql", logger.LevelInfo)
+//		sql := logger.Topic("sql")
+//		sql.Println(ctx, "query", q)
+// Then the log is "[pid][cid][sql] query ...". In JSON, it's the topic field.
+// @remark Use TopicAt for other levels, such as Warn.
+func Topic(name string) Logger {
+	return TopicAt(name, LevelTrace)
+}
+
+// Create the logger of topic at level, see Topic.
+func TopicAt(name string, level Level) Logger {
+	return &topicLogger{name: name, level: level}
+}
+
+// Set the minimum level of topic, which overrides SetLevel and SetLevelMatcher
+// for the logs of topic, for example, the "sql" at Info while "http" at Warn.
+// The topics not set use the global level.
+func SetTopicLevel(name string, level Level) {
+	updateOptions(WithTopicLevel(name, level))
+}
+
+func (v *topicLogger) Println(ctx Context, a ...interface{}) {
+	v.print(&entry{ctx: ctx, args: a, println: true, topic: v.name})
+}
+
+func (v *topicLogger) Printf(ctx Context, format string, a ...interface{}) {
+	v.print(&entry{ctx: ctx, format: format, args: a, topic: v.name})
+}
+
+func (v *topicLogger) Do(ctx Context, fn func()) {
+	if l, ok := levelLogger(v.level).(*loggerPlus); !ok || l.enabled(loadOptions(), ctx, v.name) {
+		fn()
+	}
+}
+
+// Write by the package logger of level, which is changed by Switch.
+func (v *topicLogger) print(e *entry) {
+	if l, ok := levelLogger(v.level).(*loggerPlus); ok {
+		l.output(e)
+		return
+	}
+
+	e.formatMessage(loadOptions())
+	levelLogger(v.level).Println(e.ctx, "["+v.name+"] "+e.textMessage())
+}
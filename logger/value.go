@@ -0,0 +1,85 @@
+package logger
+
+import "context"
+
+// The context with a value, for the context which is not context.Context.
+type valueContext struct {
+	parent     Context
+	key, value interface{}
+}
+
+// The valueContext of context with cid.
+type valueCidContext struct {
+	valueContext
+	cid int
+}
+
+func (v *valueCidContext) Cid() int {
+	return v.cid
+}
+
+// The context.Context with cid, whose values are in context.
+type cidValueContext struct {
+	context.Context
+	cid int
+}
+
+func (v *cidValueContext) Cid() int {
+	return v.cid
+}
+
+// Derive ctx with the value of key, which keeps the cid of ctx. For context.Context,
+// it's stored by context.WithValue, so the deadline and values are kept.
+func withValue(ctx Context, key, value interface{}) Context {
+	cid, hasCid := cidOf(ctx)
+
+	if c, ok := ctx.(context.Context); ok {
+		c = context.WithValue(c, key, value)
+		if hasCid {
+			return &cidValueContext{Context: c, cid: cid}
+		}
+		return c
+	}
+
+	if hasCid {
+		return &valueCidContext{valueContext: valueContext{parent: ctx, key: key, value: value}, cid: cid}
+	}
+	return &valueContext{parent: ctx, key: key, value: value}
+}
+
+// The value of key in ctx, nil if not found.
+func valueOf(ctx Context, key interface{}) interface{} {
+	for {
+		switch c := ctx.(type) {
+		case *valueContext:
+			if c.key == key {
+				return c.value
+			}
+			ctx = c.parent
+		case *valueCidContext:
+			if c.key == key {
+				return c.value
+			}
+			ctx = c.parent
+		case context.Context:
+			return c.Value(key)
+		default:
+			return nil
+		}
+	}
+}
+
+// The context without values derived by withValue, which decides the [pid][cid] prefix,
+// for example, nil for the values of nil context.
+func baseContext(ctx Context) Context {
+	for {
+		switch c := ctx.(type) {
+		case *valueContext:
+			ctx = c.parent
+		case *valueCidContext:
+			ctx = c.parent
+		default:
+			return ctx
+		}
+	}
+}
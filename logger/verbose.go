@@ -0,0 +1,28 @@
+//go:build !nologverbose
+// +build !nologverbose
+
+package logger
+
+// Alias for Info level println.
+// @remark It's an empty function when build with tag nologverbose.
+func I(ctx Context, a ...interface{}) {
+	Info.Println(ctx, a...)
+}
+
+// Printf for Info level log.
+// @remark It's an empty function when build with tag nologverbose.
+func If(ctx Context, format string, a ...interface{}) {
+	Info.Printf(ctx, format, a...)
+}
+
+// Info level log with fields of alternating key/value list, see Tkv.
+// @remark It's an empty function when build with tag nologverbose.
+func Ikv(ctx Context, message string, kv ...interface{}) {
+	printFields(Info, ctx, message, kvFields(kv)...)
+}
+
+// Info level log of the single preformatted string, see Ts.
+// @remark It's an empty function when build with tag nologverbose.
+func Is(ctx Context, s string) {
+	printString(Info, ctx, s)
+}
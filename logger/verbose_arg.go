@@ -0,0 +1,47 @@
+package logger
+
+import "fmt"
+
+// The arg with both short and full representation.
+type verboseArg struct {
+	v interface{}
+}
+
+// The short representation, by %v.
+func (v verboseArg) String() string {
+	return fmt.Sprintf("%v", v.v)
+}
+
+// The full representation, by %#v.
+type verboseFullArg verboseArg
+
+func (v verboseFullArg) String() string {
+	return fmt.Sprintf("%#v", v.v)
+}
+
+// Wrap v as an arg which adapts its detail to the log, for example:
+//		logger.T(ctx, "config is", logger.Verbose(cfg))
+// The text log only shows the short representation by %v, while the JSON log,
+// or the text log if SetVerbose(true), shows the full representation by %#v.
+func Verbose(v interface{}) interface{} {
+	return verboseArg{v: v}
+}
+
+// Set whether the text log shows the full representation of Verbose args, default to false.
+func SetVerbose(enabled bool) {
+	updateOptions(WithVerbose(enabled))
+}
+
+// Replace the Verbose args by their full representation, false if no Verbose args.
+func verboseArgs(args []interface{}) ([]interface{}, bool) {
+	var full []interface{}
+	for i, arg := range args {
+		if arg, ok := arg.(verboseArg); ok {
+			if full == nil {
+				full = append([]interface{}(nil), args...)
+			}
+			full[i] = verboseFullArg(arg)
+		}
+	}
+	return full, full != nil
+}
@@ -0,0 +1,26 @@
+//go:build nologverbose
+// +build nologverbose
+
+package logger
+
+// The verbose logs are removed for performance-critical builds, by:
+//		go build -tags nologverbose
+// Then logger.I, logger.If and logger.Ikv are empty functions, which are inlined to nothing,
+// while Info.Println and Info.Printf still work and are gated by level.
+// @remark The args are still evaluated by caller, so avoid expensive args.
+
+// Alias for Info level println, which is removed by tag nologverbose.
+func I(ctx Context, a ...interface{}) {
+}
+
+// Printf for Info level log, which is removed by tag nologverbose.
+func If(ctx Context, format string, a ...interface{}) {
+}
+
+// Info level log with fields of key/value list, which is removed by tag nologverbose.
+func Ikv(ctx Context, message string, kv ...interface{}) {
+}
+
+// Info level log of the single string, which is removed by tag nologverbose.
+func Is(ctx Context, s string) {
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+var verboseForLock sync.Mutex
+
+// The timer to restore the level, and the level to restore, see EnableVerboseFor.
+var verboseForTimer *time.Timer
+var verboseForLevel Level
+
+// Enable the verbose Info logs for d, then restore the level, for example, in the admin
+// endpoint to capture detail during a live incident, without forgetting to turn it off:
+//		logger.EnableVerboseFor(5 * time.Minute)
+// The repeated calls extend the duration to d from now, rather than stack, and the level
+// is restored to the one before the first call. The pending restore is canceled by Close.
+// @remark The SetLevel during the duration is overridden by the restore.
+func EnableVerboseFor(d time.Duration) {
+	verboseForLock.Lock()
+	defer verboseForLock.Unlock()
+
+	if verboseForTimer != nil {
+		verboseForTimer.Stop()
+	} else {
+		verboseForLevel = loadOptions().level
+		SetLevel(LevelInfo)
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		verboseForLock.Lock()
+		defer verboseForLock.Unlock()
+
+		// Ignore the stale timer, which fired while extended.
+		if verboseForTimer != timer {
+			return
+		}
+		verboseForTimer = nil
+		SetLevel(verboseForLevel)
+	})
+	verboseForTimer = timer
+}
+
+// Cancel the pending restore of EnableVerboseFor, and keep the level.
+func cancelVerboseFor() {
+	verboseForLock.Lock()
+	defer verboseForLock.Unlock()
+
+	if verboseForTimer != nil {
+		verboseForTimer.Stop()
+		verboseForTimer = nil
+	}
+}
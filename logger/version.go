@@ -0,0 +1,31 @@
+package logger
+
+// The version and commit of build, set by ldflags, for example:
+//		go build -ldflags "-X github.com/cheenwe/learn-go/logger.BuildVersion=v1.2.3 -X github.com/cheenwe/learn-go/logger.BuildCommit=abc1234"
+// Both are empty by default, see SetShowVersion.
+var BuildVersion, BuildCommit string
+
+// Set whether show the BuildVersion and BuildCommit in each log, default to false,
+// to correlate logs with the exact build, for example:
+//		[trace] 2006/01/02 15:04:05.000000 [pid] message version=v1.2.3 commit=abc1234
+// In JSON, they're the version and commit fields.
+// @remark The empty one is not shown, so nothing changes if not set by ldflags.
+func SetShowVersion(show bool) {
+	updateOptions(WithShowVersion(show))
+}
+
+// Append the build fields to entry, if not empty.
+func (v *entry) appendVersionFields() {
+	if BuildVersion == "" && BuildCommit == "" {
+		return
+	}
+
+	fields := append([]field(nil), v.fields...)
+	if BuildVersion != "" {
+		fields = append(fields, field{"version", BuildVersion})
+	}
+	if BuildCommit != "" {
+		fields = append(fields, field{"commit", BuildCommit})
+	}
+	v.fields = fields
+}
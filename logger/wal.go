@@ -0,0 +1,258 @@
+package logger
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// The max size of WAL, the logs are not protected by WAL when exceed.
+const maxWALSize = 16 * 1024 * 1024
+
+// The write-ahead log, protected by outputLock.
+var wal struct {
+	f    *os.File
+	size int64
+	// The logs in WAL not written to the underlayer io.
+	records int
+	// The io failed to write, whose logs are kept in WAL to keep order.
+	failed walFailed
+	// The backoff to replay the kept logs, and the time of next replay.
+	backoff time.Duration
+	retryAt time.Time
+}
+
+// Enable the write-ahead log at path for the underlayer io, for audit logs which must
+// not be lost, for example:
+//		logger.Switch(conn)
+//		if err := logger.EnableWAL("/var/lib/app/log.wal"); err != nil {
+//			return err
+//		}
+// Each log is appended to WAL with its level before written, and removed from WAL when the
+// write succeeds. If the write fails, the logs of the io are kept in WAL in order, and
+// replayed to the io of their levels with backoff, while the other io are written directly.
+// The logs left by last process are replayed now.
+// The WAL is bound to 16MB, the logs are written without WAL when exceed.
+// @remark The kept logs are pending instead of dropped, and counted when replayed, see Stats.
+// @remark The WAL is not synced for each log, which is too slow, so the logs survive the
+// 	crash of process, but might be lost when the system crashes.
+// @remark The sinks and tee are not protected, and Close closes the WAL.
+func EnableWAL(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	closeWAL()
+	wal.f = f
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		wal.size = info.Size()
+		if err := replayWAL(); err != nil {
+			handleError(loadOptions(), err)
+		}
+	}
+	return nil
+}
+
+// Write b of level to w, by WAL if enabled, return whether b is kept in WAL to write later,
+// and the error of writing b. The errors of WAL itself are reported by handleError.
+// @remark The outputLock must be held.
+func writeByWAL(o *options, level Level, w io.Writer, b []byte) (kept bool, err error) {
+	if wal.f == nil {
+		_, err = w.Write(b)
+		return false, err
+	}
+
+	if wal.records > 0 && !time.Now().Before(wal.retryAt) {
+		if err := replayWAL(); err != nil {
+			handleError(o, err)
+		}
+	}
+
+	// Write without WAL if exceed, which might be before the kept logs of w.
+	if wal.size+walHeaderSize+int64(len(b)) > maxWALSize {
+		_, err = w.Write(b)
+		return false, err
+	}
+
+	size := wal.size
+	if err := appendWAL(level, b); err != nil {
+		handleError(o, err)
+		_, err = w.Write(b)
+		return false, err
+	}
+
+	// Keep b after the kept logs of w, until replayed.
+	if wal.failed.has(level, w) {
+		return true, nil
+	}
+
+	if _, err := w.Write(b); err != nil {
+		wal.failed.add(level, w)
+		if wal.retryAt.IsZero() {
+			backoffWAL()
+		}
+		return true, err
+	}
+
+	// Remove b from WAL, which is the last one.
+	wal.size, wal.records = size, wal.records-1
+	atomic.StoreUint64(&stats.pending, uint64(wal.records))
+	if err := wal.f.Truncate(size); err != nil {
+		handleError(o, err)
+	}
+	return false, nil
+}
+
+// The header of each log in WAL, the level in one byte, then the size in 4 bytes.
+const walHeaderSize = 5
+
+// Append b of level to WAL.
+// @remark The outputLock must be held.
+func appendWAL(level Level, b []byte) error {
+	data := make([]byte, walHeaderSize+len(b))
+	data[0] = byte(level)
+	binary.BigEndian.PutUint32(data[1:walHeaderSize], uint32(len(b)))
+	copy(data[walHeaderSize:], b)
+
+	if _, err := wal.f.WriteAt(data, wal.size); err != nil {
+		return err
+	}
+	wal.size, wal.records = wal.size+int64(len(data)), wal.records+1
+	atomic.StoreUint64(&stats.pending, uint64(wal.records))
+	return nil
+}
+
+// Write the logs in WAL to the underlayer io of their levels, such as SwitchPerLevelFiles,
+// and remove the written ones from WAL. The logs of the io failed to write are kept in
+// order, and replayed again after backoff. Return the error of WAL itself.
+// @remark The outputLock must be held.
+func replayWAL() error {
+	data, err := ioutil.ReadAll(io.NewSectionReader(wal.f, 0, wal.size))
+	if err != nil {
+		return err
+	}
+
+	var kept []byte
+	var failed walFailed
+	records, written := 0, false
+	// The last log might be partial if the process crashed, which is dropped.
+	for len(data) >= walHeaderSize {
+		level, size := Level(data[0]), int(binary.BigEndian.Uint32(data[1:walHeaderSize]))
+		if len(data) < walHeaderSize+size {
+			break
+		}
+		record := data[:walHeaderSize+size]
+		data = data[walHeaderSize+size:]
+
+		w := walWriter(level)
+		if !failed.has(level, w) {
+			if _, err := w.Write(record[walHeaderSize:]); err == nil {
+				if level >= LevelInfo && level <= LevelError {
+					atomic.AddUint64(&stats.counts[level], 1)
+				}
+				written = true
+				continue
+			}
+			failed.add(level, w)
+		}
+		kept, records = append(kept, record...), records+1
+	}
+
+	wal.failed = failed
+	if records == 0 {
+		wal.backoff, wal.retryAt = 0, time.Time{}
+		return truncateWAL()
+	}
+
+	backoffWAL()
+	if !written && int64(len(kept)) == wal.size {
+		wal.records = records
+		atomic.StoreUint64(&stats.pending, uint64(wal.records))
+		return nil
+	}
+	return keepWAL(kept, records)
+}
+
+// Delay the next replay, the backoff is doubled for each failure until the max.
+// @remark The outputLock must be held.
+func backoffWAL() {
+	if wal.backoff *= 2; wal.backoff < minSocketBackoff {
+		wal.backoff = minSocketBackoff
+	} else if wal.backoff > maxSocketBackoff {
+		wal.backoff = maxSocketBackoff
+	}
+	wal.retryAt = time.Now().Add(wal.backoff)
+}
+
+// The io which failed to write in WAL, by level and writer.
+type walFailed struct {
+	levels  map[Level]bool
+	writers []io.Writer
+}
+
+func (v *walFailed) add(level Level, w io.Writer) {
+	if v.levels == nil {
+		v.levels = make(map[Level]bool)
+	}
+	v.levels[level] = true
+	v.writers = append(v.writers, w)
+}
+
+// Whether the io of level or w failed, for the levels might share one io.
+func (v *walFailed) has(level Level, w io.Writer) bool {
+	if v.levels[level] {
+		return true
+	}
+	for _, f := range v.writers {
+		if sameWriter(f, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// The underlayer io of level, to replay the logs in WAL.
+func walWriter(level Level) io.Writer {
+	if v, ok := levelLogger(level).(*loggerPlus); ok {
+		return fallbackWriter(v.logger.Writer())
+	}
+	return ioutil.Discard
+}
+
+// Keep only data in WAL, which are the records not written.
+// @remark The outputLock must be held.
+func keepWAL(data []byte, records int) error {
+	if _, err := wal.f.WriteAt(data, 0); err != nil {
+		return err
+	}
+	wal.size, wal.records = int64(len(data)), records
+	atomic.StoreUint64(&stats.pending, uint64(wal.records))
+	return wal.f.Truncate(wal.size)
+}
+
+// @remark The outputLock must be held.
+func truncateWAL() error {
+	wal.size, wal.records = 0, 0
+	atomic.StoreUint64(&stats.pending, 0)
+	return wal.f.Truncate(0)
+}
+
+// Close the WAL, the kept logs are left for next process.
+// @remark The outputLock must be held.
+func closeWAL() error {
+	if wal.f == nil {
+		return nil
+	}
+	err := wal.f.Close()
+	wal.f, wal.size, wal.records, wal.failed = nil, 0, 0, walFailed{}
+	wal.backoff, wal.retryAt = 0, time.Time{}
+	atomic.StoreUint64(&stats.pending, 0)
+	return err
+}
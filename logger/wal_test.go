@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The writer which fails until recovered.
+type testFailingWriter struct {
+	bytes.Buffer
+	broken bool
+}
+
+func (v *testFailingWriter) Write(p []byte) (int, error) {
+	if v.broken {
+		return 0, errors.New("broken")
+	}
+	return v.Buffer.Write(p)
+}
+
+func TestWALReplayByLevel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var trace bytes.Buffer
+	warn := &testFailingWriter{broken: true}
+	Trace = newLoggerPlus(&trace, LevelTrace)
+	Warn = newLoggerPlus(warn, LevelWarn)
+	defer Switch(os.Stdout)
+
+	if err := EnableWAL(filepath.Join(dir, "log.wal")); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		outputLock.Lock()
+		defer outputLock.Unlock()
+		closeWAL()
+	}()
+
+	before := Stats()
+	W(nil, "warn1")
+	T(nil, "trace1")
+	if s := trace.String(); strings.Contains(s, "warn1") || !strings.Contains(s, "trace1") {
+		t.Errorf("unexpected trace %q", s)
+	}
+
+	// The warn1 is pending, and trace1 is written, neither is dropped.
+	s := Stats()
+	if s.Dropped != before.Dropped || s.Pending != 1 || s.Counts[LevelTrace] != before.Counts[LevelTrace]+1 ||
+		s.Counts[LevelWarn] != before.Counts[LevelWarn] {
+		t.Errorf("unexpected stats %+v, before %+v", s, before)
+	}
+
+	// The warn2 is kept after warn1 until the backoff is passed.
+	warn.broken = false
+	W(nil, "warn2")
+	if warn.Len() != 0 || Stats().Pending != 2 {
+		t.Errorf("unexpected warn %q before backoff", warn.String())
+	}
+
+	outputLock.Lock()
+	wal.retryAt = time.Time{}
+	outputLock.Unlock()
+	W(nil, "warn3")
+	if s := warn.String(); strings.Contains(s, "trace1") || strings.Count(s, "] warn") != 3 ||
+		strings.Index(s, "warn1") > strings.Index(s, "warn2") || strings.Index(s, "warn2") > strings.Index(s, "warn3") {
+		t.Errorf("unexpected warn %q", s)
+	}
+	if s := Stats(); s.Pending != 0 || s.Counts[LevelWarn] != before.Counts[LevelWarn]+3 || s.Dropped != before.Dropped {
+		t.Errorf("unexpected stats %+v, before %+v", s, before)
+	}
+	if strings.Count(trace.String(), "trace1") != 1 {
+		t.Errorf("unexpected trace %q", trace.String())
+	}
+}